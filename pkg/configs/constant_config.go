@@ -10,6 +10,28 @@ var InternalPaths = map[string]bool{
 	"/external": true,
 }
 
+// 요청 ID 설정
+const (
+	// 요청 ID 헤더 (클라이언트/nginx가 보낸 값을 그대로 사용)
+	RequestIdHeader = "X-Request-ID"
+	// fiber Locals에 저장하는 요청 ID 키
+	RequestIdLocalKey = "requestId"
+	// SSE 토큰을 발급한 검색 요청의 요청 ID를 담는 fiber Locals 키
+	IssuerRequestIdLocalKey = "issuerRequestId"
+	// 허용하는 요청 ID 최대 길이
+	MaxRequestIdLength = 128
+)
+
+// 수신 경로(/internal, /metrics) 인증 설정
+const (
+	// HMAC 서명 헤더 (서명 대상: 메서드 \n 요청 경로 \n 타임스탬프 \n 본문)
+	IngestKeyIdHeader     = "X-Ndns-Key-Id"
+	IngestTimestampHeader = "X-Ndns-Timestamp"
+	IngestSignatureHeader = "X-Ndns-Signature"
+	// fiber Locals에 저장하는 인증된 호출자 키
+	IngestCredentialLocalKey = "ingestCredential"
+)
+
 // 타임아웃 설정
 const (
 	// 프록시 요청 타임아웃
@@ -18,6 +40,44 @@ const (
 	HealthCheckTimeout = 2 * time.Second
 )
 
+// 저장소 설정
+const (
+	// 저장소 요청 타임아웃
+	StoreTimeout = 2 * time.Second
+
+	// Redis 키 구조
+	RedisKeyServerList   = "ndns:router:servers:list"   // Set: 등록된 서버 ID 목록
+	RedisKeyServerStatus = "ndns:router:servers:status" // Hash: 서버 전체 정보 (JSON)
+	RedisKeyServerHealth = "ndns:router:servers:health" // Hash: 서버 상태 (빠른 조회용)
+	RedisKeyServerLoad   = "ndns:router:servers:load"   // Hash: 서버 부하 정보 (빠른 조회용)
+)
+
+// 이벤트 버스 설정
+const (
+	// 구독자별 기본 이벤트 버퍼 크기 (가득 차면 이벤트 유실)
+	EventBufferSize = 64
+	// 이 값 이상 점수가 변하면 점수 변경 이벤트 발행
+	ScoreChangeThreshold = 1.0
+)
+
+// 드레인 설정
+const (
+	// 드레인 진행 상황 점검 주기
+	DrainCheckInterval = 1 * time.Second
+)
+
+// 서버 상태 머신 설정
+const (
+	// 메트릭 갱신 중단, 관리자 지정 만료 점검 주기
+	StatusCheckInterval = 5 * time.Second
+)
+
+// SSE 스트림 설정
+const (
+	// 전달할 메시지가 없을 때 연결 유지를 위해 하트비트를 보내는 주기 (SSE_IDLE_TIMEOUT보다 짧아야 함)
+	SseHeartbeatInterval = 30 * time.Second
+)
+
 // 재시도 설정
 const (
 	// 최대 재시도 횟수
@@ -31,6 +91,7 @@ const (
 	// 서버 점수 기준
 	ScoreExcellent = 80.0 // 최상 기준 점수
 	ScoreGood      = 60.0 // 중간 기준 점수
+	ScoreWarning   = 30.0 // 경고 기준 점수 (미만이면 비정상)
 
 	// 동시성 제어
 	MaxConcurrentRequests = 10                     // 서버당 최대 동시 요청 수
@@ -3,6 +3,7 @@ package configs
 import (
 	"log"
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
@@ -32,6 +33,186 @@ type EnvConfig struct {
 			CloudRun  int `env:"WEIGHT_CLOUD_RUN" envDefault:"15"` // Cloud Run 라우팅 비율
 			Lambda    int `env:"WEIGHT_LAMBDA" envDefault:"15"`    // Lambda 라우팅 비율
 		}
+		// 서버 선택 우선순위 (라벨 셀렉터 목록, ';'로 구분, 앞쪽이 우선)
+		PriorityLimit2  []string `env:"ROUTING_PRIORITY_LIMIT_2" envSeparator:";" envDefault:"serverId=ndns-api1;serverId=ndns-api2;serverId=ndns-external"`
+		PriorityLimit10 []string `env:"ROUTING_PRIORITY_LIMIT_10" envSeparator:";" envDefault:"serverId=ndns-external;serverId=ndns-api1;serverId=ndns-api2;serverId=ndns-api3"`
+		PriorityDefault []string `env:"ROUTING_PRIORITY_DEFAULT" envSeparator:";" envDefault:"serverId=ndns-external;serverId=ndns-api1;serverId=ndns-api3;serverId=ndns-api2"`
+		// 점수와 관계없이 양호(Good) 서버로 분류할 라벨 셀렉터
+		ForceGoodSelector string `env:"ROUTING_FORCE_GOOD_SELECTOR" envDefault:"serverType=wsl"`
+		// 라우터 위치 (비어 있으면 존 우선 라우팅 비활성화)
+		Zone   string `env:"ROUTER_ZONE"`
+		Region string `env:"ROUTER_REGION"`
+		// 같은 존 정상 서버가 이 수보다 적으면 다른 존으로 넘김
+		ZoneSpillMinHealthy int `env:"ZONE_SPILL_MIN_HEALTHY" envDefault:"1"`
+		// 같은 존 서버의 동시 요청 사용률이 이 값 이상이면 포화로 보고 다른 존으로 넘김 (0-1)
+		ZoneSpillSaturation float64 `env:"ZONE_SPILL_SATURATION" envDefault:"0.8"`
+	}
+	// 서버 디스커버리 설정
+	Discovery struct {
+		// 정적 서버 목록 (URL 또는 "서버ID=URL", 쉼표로 구분)
+		ServerList []string `env:"SERVER_LIST" envSeparator:","`
+		// 서버 목록 파일 (JSON/YAML, 변경 시 자동 반영)
+		File         string        `env:"DISCOVERY_FILE"`
+		FileInterval time.Duration `env:"DISCOVERY_FILE_INTERVAL" envDefault:"5s"`
+		// DNS 디스커버리 (호스트 이름의 A/AAAA 또는 SRV 레코드를 TTL 주기로 조회)
+		DnsName     string        `env:"DISCOVERY_DNS_NAME"`
+		DnsType     string        `env:"DISCOVERY_DNS_TYPE" envDefault:"a"`  // a (A/AAAA), srv
+		DnsPort     int           `env:"DISCOVERY_DNS_PORT" envDefault:"80"` // A/AAAA 레코드에 사용할 포트
+		DnsScheme   string        `env:"DISCOVERY_DNS_SCHEME" envDefault:"http"`
+		DnsResolver string        `env:"DISCOVERY_DNS_RESOLVER"` // 비어 있으면 /etc/resolv.conf 사용
+		DnsMinTtl   time.Duration `env:"DISCOVERY_DNS_MIN_TTL" envDefault:"5s"`
+		DnsMaxTtl   time.Duration `env:"DISCOVERY_DNS_MAX_TTL" envDefault:"5m"`
+		// 쿠버네티스 EndpointSlice 디스커버리 (서비스의 준비된 엔드포인트를 감시)
+		K8sService    string `env:"DISCOVERY_K8S_SERVICE"`
+		K8sNamespace  string `env:"DISCOVERY_K8S_NAMESPACE" envDefault:"default"`
+		K8sPortName   string `env:"DISCOVERY_K8S_PORT_NAME"` // 비어 있으면 첫 번째 포트 사용
+		K8sScheme     string `env:"DISCOVERY_K8S_SCHEME" envDefault:"http"`
+		K8sPodLabels  bool   `env:"DISCOVERY_K8S_POD_LABELS" envDefault:"true"` // 파드 라벨 복사 여부
+		K8sKubeconfig string `env:"KUBECONFIG"`                                 // 비어 있으면 클러스터 내부 설정 사용
+		// DNS/쿠버네티스로 발견한 서버에 붙일 기본 라벨 (key=value, 쉼표로 구분)
+		// 발견한 서버는 자신의 ID로 메트릭을 푸시할 수 없으므로 기본적으로 수집(scrape) 대상으로 지정
+		Labels []string `env:"DISCOVERY_LABELS" envSeparator:"," envDefault:"metricsMode=scrape"`
+	}
+	// 서버별 메트릭 이력 설정
+	History struct {
+		// "해상도:보관 기간" 단계 목록 (쉼표로 구분, 오래된 데이터일수록 거친 해상도로 보관)
+		Tiers string `env:"HISTORY_TIERS" envDefault:"10s:1h,1m:6h,5m:24h"`
+	}
+	// 메트릭 수집(scrape) 설정 - 푸시 대신 라우터가 서버의 메트릭 경로를 주기적으로 조회
+	Scrape struct {
+		// 수집 대상 서버 라벨 셀렉터 (비어 있으면 수집하지 않음, 일치하지 않는 서버는 푸시 사용)
+		Selector string        `env:"SCRAPE_SELECTOR" envDefault:"metricsMode=scrape"`
+		Interval time.Duration `env:"SCRAPE_INTERVAL" envDefault:"15s"`
+		Timeout  time.Duration `env:"SCRAPE_TIMEOUT" envDefault:"5s"`
+		Path     string        `env:"SCRAPE_PATH" envDefault:"/metrics"`
+		Format   string        `env:"SCRAPE_FORMAT" envDefault:"auto"` // auto, json, prometheus
+		// 연속 수집 실패가 이 횟수 이상이면 unknown으로 전환
+		FailureThreshold int `env:"SCRAPE_FAILURE_THRESHOLD" envDefault:"2"`
+		// Prometheus 텍스트 형식에서 읽을 메트릭 이름 (같은 이름의 시계열은 합산)
+		MetricCPU           string `env:"SCRAPE_METRIC_CPU" envDefault:"cpu_usage"`
+		MetricMemory        string `env:"SCRAPE_METRIC_MEMORY" envDefault:"memory_usage"`
+		MetricErrorRate     string `env:"SCRAPE_METRIC_ERROR_RATE" envDefault:"error_rate"`
+		MetricLatency       string `env:"SCRAPE_METRIC_LATENCY" envDefault:"response_time"`
+		MetricTotalRequests string `env:"SCRAPE_METRIC_TOTAL_REQUESTS" envDefault:"total_requests"`
+		MetricErrorRequests string `env:"SCRAPE_METRIC_ERROR_REQUESTS" envDefault:"error_requests"`
+	}
+	// 수신 경로(/internal, /metrics) 인증 설정
+	IngestAuth struct {
+		// 인증 정보 파일 (JSON/YAML, 필수)
+		CredentialsFile string `env:"INGEST_CREDENTIALS_FILE"`
+		// 인증 없이 수신 (개발 환경용, 인증 정보 파일 대신 명시적으로 지정해야 함)
+		Disabled bool `env:"INGEST_AUTH_DISABLED" envDefault:"false"`
+		// 서명 시각 허용 오차 (이 구간 안에서 같은 서명은 한 번만 허용)
+		ReplayWindow time.Duration `env:"INGEST_REPLAY_WINDOW" envDefault:"5m"`
+	}
+	// 프록시 접근 로그 설정 (요청마다 선택된 서버, 시도 횟수, 폴백 이유 기록)
+	AccessLog struct {
+		Enabled bool     `env:"ACCESS_LOG_ENABLED" envDefault:"true"`
+		Format  string   `env:"ACCESS_LOG_FORMAT" envDefault:"json"`                    // json, template
+		Outputs []string `env:"ACCESS_LOG_OUTPUT" envSeparator:"," envDefault:"stdout"` // stdout, file
+		// 파일 출력 경로
+		FilePath string `env:"ACCESS_LOG_FILE" envDefault:"logs/access.log"`
+		// template 형식에서 사용할 text/template (필드는 AccessLogRecord)
+		Template string `env:"ACCESS_LOG_TEMPLATE" envDefault:"{{.Time.Format \"2006-01-02T15:04:05.000Z07:00\"}} [{{.RequestId}}] {{.ClientIp}} {{.Method}} {{.Path}} {{.Status}} server={{.ServerId}} attempts={{.Attempts}} fallback={{.Fallback}} forced={{.Forced}} upstream={{.UpstreamStatus}} {{.UpstreamLatencyMs}}ms total={{.LatencyMs}}ms in={{.BytesIn}} out={{.BytesOut}}"`
+		// 성공한 요청 기록 비율 (실패하거나 폴백된 요청은 항상 기록)
+		SuccessSampleRate float64 `env:"ACCESS_LOG_SUCCESS_SAMPLE_RATE" envDefault:"1"`
+		// 기록 대기 큐 크기 (가득 차면 로그를 버리고 유실 건수만 셈)
+		QueueSize int `env:"ACCESS_LOG_QUEUE_SIZE" envDefault:"4096"`
+	}
+	// OpenTelemetry 트레이스 설정
+	Tracing struct {
+		Exporter     string  `env:"TRACING_EXPORTER" envDefault:"none"` // none, stdout, otlp (쉼표로 여러 개)
+		OtlpEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4318"`
+		OtlpInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+		SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"` // 부모 스팬이 없을 때 샘플링 비율
+		ServiceName  string  `env:"TRACING_SERVICE_NAME" envDefault:"ndns-router"`
+	}
+	// SSE 결과 스트림 설정
+	Sse struct {
+		// 구독자 최대 연결 시간, 메시지나 하트비트를 쓰지 못한 채 유지할 수 있는 시간, 정리 주기
+		MaxLifetime  time.Duration `env:"SSE_MAX_LIFETIME" envDefault:"5m"`
+		IdleTimeout  time.Duration `env:"SSE_IDLE_TIMEOUT" envDefault:"2m"`
+		ReapInterval time.Duration `env:"SSE_REAP_INTERVAL" envDefault:"10s"`
+		// reqId마다 재연결(Last-Event-ID) 시 다시 보낼 최근 이벤트 수와 보관 기간
+		ReplaySize int           `env:"SSE_REPLAY_SIZE" envDefault:"50"`
+		ReplayTtl  time.Duration `env:"SSE_REPLAY_TTL" envDefault:"5m"`
+		// 클라이언트 재연결 대기 시간 (retry:)
+		RetryInterval time.Duration `env:"SSE_RETRY_INTERVAL" envDefault:"3s"`
+		// 구독자가 연결되기 전에 도착한 메시지 보관 (reqId별 개수, 보관 기간, 보관할 수 있는 reqId 수)
+		MailboxSize  int           `env:"SSE_MAILBOX_SIZE" envDefault:"20"`
+		MailboxTtl   time.Duration `env:"SSE_MAILBOX_TTL" envDefault:"1m"`
+		MaxMailboxes int           `env:"SSE_MAX_MAILBOXES" envDefault:"10000"`
+		// 연결 전 메시지를 보관하려고 기억하는 이 인스턴스가 발급한 reqId 수 (발급 후 SSE_MAILBOX_TTL 동안)
+		MaxIssued int `env:"SSE_MAX_ISSUED" envDefault:"100000"`
+		// 인스턴스 간 메시지 발행/구독 통로 (memory: 단일 인스턴스, redis: REDIS_ADDR의 pub/sub 채널)
+		Broker        string `env:"SSE_BROKER" envDefault:"memory"`
+		BrokerChannel string `env:"SSE_BROKER_CHANNEL" envDefault:"ndns:router:sse"`
+	}
+	// StatsD/DogStatsD 전송 설정
+	Statsd struct {
+		Address       string        `env:"STATSD_ADDR"`                              // host:port (비어 있으면 비활성화)
+		Format        string        `env:"STATSD_FORMAT" envDefault:"dogstatsd"`     // statsd, dogstatsd
+		Prefix        string        `env:"STATSD_PREFIX" envDefault:"ndns_router."`  // 메트릭 이름 접두사
+		Tags          []string      `env:"STATSD_TAGS" envSeparator:","`             // 전역 태그 (dogstatsd, 예: env:prod)
+		FlushInterval time.Duration `env:"STATSD_FLUSH_INTERVAL" envDefault:"1s"`    // 패킷이 차지 않아도 보내는 주기
+		MaxPacketSize int           `env:"STATSD_MAX_PACKET_SIZE" envDefault:"1432"` // UDP 패킷 최대 크기 (bytes)
+		QueueSize     int           `env:"STATSD_QUEUE_SIZE" envDefault:"4096"`      // 전송 대기 큐 크기 (가득 차면 버림)
+	}
+	// Prometheus 노출 설정
+	Prometheus struct {
+		Enabled bool   `env:"PROMETHEUS_ENABLED" envDefault:"true"`
+		Path    string `env:"PROMETHEUS_PATH" envDefault:"/prometheus"` // 내부 관리 경로(/metrics 등)와 겹치면 안 됨
+	}
+	// 서버 레지스트리 저장소 설정
+	Store struct {
+		Type     string `env:"STORE_TYPE" envDefault:"memory"`                 // memory, file, redis
+		FilePath string `env:"STORE_FILE_PATH" envDefault:"data/servers.json"` // file 저장소 경로
+	}
+	// Redis 설정
+	Redis struct {
+		Enabled  bool   `env:"USE_REDIS" envDefault:"false"` // true면 STORE_TYPE과 관계없이 redis 사용
+		Addr     string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
+		Password string `env:"REDIS_PASSWORD"`
+		DB       int    `env:"REDIS_DB" envDefault:"0"`
+	}
+	// 드레인 설정
+	Drain struct {
+		Timeout time.Duration `env:"DRAIN_TIMEOUT" envDefault:"5m"`  // 진행 중 요청 대기 최대 시간
+		Action  string        `env:"DRAIN_ACTION" envDefault:"park"` // 완료 후 처리 (remove, park)
+	}
+	// 서버 상태 머신 설정
+	Status struct {
+		// 점수 경계에서 상태가 흔들리지 않도록 경계 위아래로 두는 여유 점수
+		Hysteresis float64 `env:"STATUS_HYSTERESIS" envDefault:"5"`
+		// 연속 실패/성공 횟수 기준
+		ProbeFailureThreshold int `env:"STATUS_PROBE_FAILURE_THRESHOLD" envDefault:"3"`
+		ProbeSuccessThreshold int `env:"STATUS_PROBE_SUCCESS_THRESHOLD" envDefault:"2"`
+		ProxyFailureThreshold int `env:"STATUS_PROXY_FAILURE_THRESHOLD" envDefault:"3"`
+		// 헬스 체크 경로 (비어 있으면 헬스 체크하지 않음), 주기, 요청 제한 시간
+		ProbePath     string        `env:"STATUS_PROBE_PATH"`
+		ProbeInterval time.Duration `env:"STATUS_PROBE_INTERVAL" envDefault:"10s"`
+		ProbeTimeout  time.Duration `env:"STATUS_PROBE_TIMEOUT" envDefault:"2s"`
+		// 이 시간 동안 메트릭이 없으면 unknown으로 전환 (0이면 비활성화)
+		StaleAfter time.Duration `env:"STATUS_STALE_AFTER" envDefault:"2m"`
+		// 서버별 보관할 상태 전이 기록 수
+		TransitionHistory int `env:"STATUS_TRANSITION_HISTORY" envDefault:"100"`
+	}
+	// 점수 계산 설정
+	Scoring struct {
+		// 지표별 가중치
+		WeightCPU       float64 `env:"SCORE_WEIGHT_CPU" envDefault:"0.3"`
+		WeightMemory    float64 `env:"SCORE_WEIGHT_MEMORY" envDefault:"0.2"`
+		WeightErrorRate float64 `env:"SCORE_WEIGHT_ERROR_RATE" envDefault:"0.3"`
+		WeightLatency   float64 `env:"SCORE_WEIGHT_LATENCY" envDefault:"0.2"`
+		// 지표별 정규화 범위 (최소,최대) - 최소 이하면 만점, 최대 이상이면 0점
+		CPURange       []float64 `env:"SCORE_RANGE_CPU" envSeparator:"," envDefault:"0,100"`       // CPU 사용률 (%)
+		MemoryRange    []float64 `env:"SCORE_RANGE_MEMORY" envSeparator:"," envDefault:"0,100"`    // 메모리 사용률 (%)
+		ErrorRateRange []float64 `env:"SCORE_RANGE_ERROR_RATE" envSeparator:"," envDefault:"0,10"` // 에러율 (%)
+		LatencyRange   []float64 `env:"SCORE_RANGE_LATENCY" envSeparator:"," envDefault:"50,2000"` // 응답시간 (ms)
+		// 감점 규칙 (예: "errorRate>5:cap=50;cpuUsage>90:minus=20")
+		Penalties []string `env:"SCORE_PENALTIES" envSeparator:";" envDefault:"errorRate>5:cap=50"`
+		// 서버가 보낸 점수 반영 비율 (0: 무시, 1: 그대로 사용)
+		PushedWeight float64 `env:"SCORE_PUSHED_WEIGHT" envDefault:"0"`
 	}
 }
 
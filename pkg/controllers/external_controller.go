@@ -2,13 +2,19 @@ package controllers
 
 import (
 	"bufio"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sh5080/ndns-router/pkg/configs"
 	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
 	"github.com/sh5080/ndns-router/pkg/types/dtos"
 	"github.com/sh5080/ndns-router/pkg/utils"
 	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ExternalController struct {
@@ -30,32 +36,62 @@ func (c *ExternalController) GetActiveConnections(ctx *fiber.Ctx) error {
 	return utils.SendSuccessData(ctx, dtos.ActiveConnections{
 		TotalConnections: len(activeConnections),
 		Connections:      activeConnections,
+		PendingMailboxes: c.sseManager.PendingMailboxes(),
+		MailboxEvictions: c.sseManager.MailboxEvictions(),
+		Reaped:           c.sseManager.ReapCounts(),
 	})
 }
 
 func (c *ExternalController) SseHandler(ctx *fiber.Ctx) error {
-	reqId := ctx.Query("reqId")
+	// JWT 미들웨어가 토큰에서 검증한 reqId 우선 사용
+	reqId, _ := ctx.Locals("reqId").(string)
+	if reqId == "" {
+		// 스트림이 끝날 때까지 키로 사용하므로 fiber 버퍼를 참조하지 않도록 복사
+		reqId = strings.Clone(ctx.Query("reqId"))
+	}
 	if reqId == "" {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "reqId 파라미터가 필요합니다")
 	}
 
-	messageChan := make(chan string, 10)
+	// 재연결한 클라이언트가 마지막으로 받은 이벤트 ID (EventSource는 헤더로, 직접 재연결하는 클라이언트는 쿼리로 전달)
+	lastEventIdValue := ctx.Get("Last-Event-ID")
+	if lastEventIdValue == "" {
+		lastEventIdValue = ctx.Query("lastEventId")
+	}
+	var lastEventId uint64
+	if lastEventIdValue != "" {
+		parsed, err := strconv.ParseUint(lastEventIdValue, 10, 64)
+		if err != nil {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "Last-Event-ID는 숫자여야 합니다")
+		}
+		lastEventId = parsed
+	}
+
+	messageChan := make(chan types.SseEvent, 10)
 
 	ctx.Set("Content-Type", "text/event-stream")
 	ctx.Set("Cache-Control", "no-cache")
 	ctx.Set("Connection", "keep-alive")
 
-	utils.Infof("[SSE] 새로운 연결 시작: %s", reqId)
+	issuerRequestId, _ := ctx.Locals(configs.IssuerRequestIdLocalKey).(string)
+	utils.Infof("[SSE] 새로운 연결 시작: %s (Last-Event-ID: %d, 발급 요청: %s)", reqId, lastEventId, issuerRequestId)
 
-	c.sseManager.Register(reqId, messageChan)
+	// 연결 등록 스팬 (스트림은 오래 유지되므로 등록 시점까지만 기록)
+	_, span := utils.StartSpan(utils.ExtractTraceContext(ctx.UserContext(), &ctx.Request().Header), "sse.register",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("ndns.req_id", reqId)))
+	info, replay := c.sseManager.Register(reqId, messageChan, lastEventId)
+	subscriberId := info.SubscriberId
+	span.End()
 
 	ctx.Context().SetConnectionClose()
+	retryInterval := c.sseManager.RetryInterval()
 
 	ctx.Context().Response.SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
 		defer func() {
-			utils.Infof("[SSE] 스트림 종료, 채널 정리 시작 - reqId: %s", reqId)
-			c.sseManager.Deregister(reqId)
-			utils.Infof("[SSE] 스트림 종료, 채널 정리 완료 - reqId: %s", reqId)
+			utils.Infof("[SSE] 스트림 종료, 채널 정리 시작 - reqId: %s, 구독자: %s", reqId, subscriberId)
+			c.sseManager.Deregister(reqId, subscriberId)
+			utils.Infof("[SSE] 스트림 종료, 채널 정리 완료 - reqId: %s, 구독자: %s", reqId, subscriberId)
 		}()
 
 		ssePayload := dtos.SsePayload{
@@ -65,32 +101,55 @@ func (c *ExternalController) SseHandler(ctx *fiber.Ctx) error {
 			},
 		}
 
+		if err := utils.SendSseRetry(w, retryInterval); err != nil {
+			utils.Infof("[SSE] 초기 메시지 전송 실패 - reqId: %s, error: %v", reqId, err)
+			return
+		}
 		if err := utils.SendSseEvent(w, &ssePayload); err != nil {
 			utils.Infof("[SSE] 초기 메시지 전송 실패 - reqId: %s, error: %v", reqId, err)
 			return
 		}
 
-		ticker := time.NewTicker(30 * time.Second)
+		// 재연결 전에 놓친 이벤트를 먼저 보낸 뒤 실시간 전달로 넘어감
+		for _, event := range replay {
+			if err := utils.SendSseEvent(w, newSseMessagePayload(event)); err != nil {
+				utils.Infof("[SSE] 놓친 이벤트 재전송 실패 (reqId: %s): %v", reqId, err)
+				return
+			}
+		}
+		if len(replay) > 0 {
+			utils.Infof("[SSE] 놓친 이벤트 %d개 재전송 완료 - reqId: %s", len(replay), reqId)
+		}
+
+		// 유휴 연결 판단은 클라이언트에 실제로 쓴 시각 기준 (메시지와 하트비트 모두)
+		ticker := time.NewTicker(configs.SseHeartbeatInterval)
 		defer ticker.Stop()
 
 		for {
 			select {
-			case msg, ok := <-messageChan:
+			case event, ok := <-messageChan:
 				if !ok {
 					utils.Infof("[SSE] 메시지 채널이 닫힘 - reqId: %s", reqId)
+					// 라우터가 끊은 경우 사유를 알려 클라이언트가 재연결 여부를 판단하도록 함
+					if info.CloseReason != "" {
+						closePayload := dtos.SsePayload{
+							Type: dtos.SseClose,
+							Data: map[string]interface{}{
+								"reason": info.CloseReason,
+							},
+						}
+						if err := utils.SendSseEvent(w, &closePayload); err != nil {
+							utils.Infof("[SSE] 종료 이벤트 전송 실패 (reqId: %s): %v", reqId, err)
+						}
+					}
 					return
 				}
-				utils.Infof("[SSE] 클라이언트에게 결과 전송: %s", msg)
-				ssePayload := dtos.SsePayload{
-					Type: dtos.SseMessage,
-					Data: map[string]interface{}{
-						"result": msg,
-					},
-				}
-				if err := utils.SendSseEvent(w, &ssePayload); err != nil {
+				utils.Infof("[SSE] 클라이언트에게 결과 전송 (이벤트: %d): %s", event.Id, event.Message)
+				if err := utils.SendSseEvent(w, newSseMessagePayload(event)); err != nil {
 					utils.Infof("[SSE] 클라이언트 연결 종료 (reqId: %s): %v", reqId, err)
 					return
 				}
+				c.sseManager.Touch(reqId, subscriberId)
 			case <-ticker.C:
 				utils.Infof("[SSE] 하트비트 전송 시도 - reqId: %s", reqId)
 				ssePayload := dtos.SsePayload{
@@ -103,6 +162,7 @@ func (c *ExternalController) SseHandler(ctx *fiber.Ctx) error {
 					utils.Infof("[SSE] 하트비트 전송 실패 (reqId: %s): %v", reqId, err)
 					return
 				}
+				c.sseManager.Touch(reqId, subscriberId)
 			}
 		}
 	}))
@@ -110,7 +170,23 @@ func (c *ExternalController) SseHandler(ctx *fiber.Ctx) error {
 	return nil
 }
 
+// newSseMessagePayload 결과 이벤트를 SSE message 페이로드로 변환
+func newSseMessagePayload(event types.SseEvent) *dtos.SsePayload {
+	return &dtos.SsePayload{
+		Id:   event.Id,
+		Type: dtos.SseMessage,
+		Data: map[string]interface{}{
+			"result": event.Message,
+		},
+	}
+}
+
 func (c *ExternalController) SendMessage(ctx *fiber.Ctx) error {
+	if !utils.AllowsAnalysis(ctx) {
+		utils.Warnf("SSE 메시지 전송 권한 없음 (호출자: %s)", utils.IngestCallerId(ctx))
+		return utils.SendError(ctx, fiber.StatusForbidden, "분석결과를 전달할 권한이 없습니다")
+	}
+
 	req := new(dtos.MessageRequest)
 	if err := ctx.BodyParser(req); err != nil {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식")
@@ -121,7 +197,7 @@ func (c *ExternalController) SendMessage(ctx *fiber.Ctx) error {
 	}
 
 	utils.Infof("[SSE] Send 시도 - reqId: %s", req.ReqId)
-	c.sseManager.Send(req.ReqId, req.Message)
+	c.sseManager.Send(ctx.UserContext(), req.ReqId, req.Message)
 
 	return utils.SendSuccessMessage(ctx, "메시지가 성공적으로 전송되었습니다")
 }
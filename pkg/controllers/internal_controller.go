@@ -8,6 +8,8 @@ import (
 	"github.com/sh5080/ndns-router/pkg/interfaces"
 	"github.com/sh5080/ndns-router/pkg/types"
 	"github.com/sh5080/ndns-router/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type InternalController struct {
@@ -28,6 +30,14 @@ func (c *InternalController) HandleOptimalServer(ctx *fiber.Ctx) error {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식입니다")
 	}
 
+	// 호출자가 갱신할 수 없는 서버가 하나라도 있으면 요청 전체 거부
+	for _, serverInfo := range request.Servers {
+		if !utils.AllowsServer(ctx, serverInfo.ServerId) {
+			utils.Warnf("서버 정보 갱신 권한 없음: %s (호출자: %s)", serverInfo.ServerId, utils.IngestCallerId(ctx))
+			return utils.SendError(ctx, fiber.StatusForbidden, "서버 정보를 갱신할 권한이 없습니다: "+serverInfo.ServerId)
+		}
+	}
+
 	utils.Infof("서버 정보 수신 (총 %d개):", len(request.Servers))
 
 	for _, serverInfo := range request.Servers {
@@ -35,14 +45,17 @@ func (c *InternalController) HandleOptimalServer(ctx *fiber.Ctx) error {
 			ServerId:      serverInfo.ServerId,
 			ServerUrl:     serverInfo.ServerUrl,
 			ServerType:    serverInfo.ServerType,
-			CurrentStatus: string(types.StatusExcellent),
+			Zone:          serverInfo.Zone,
+			Region:        serverInfo.Region,
+			Labels:        serverInfo.Labels,
+			CurrentStatus: string(types.StatusUnknown), // 상태는 상태 머신이 점수로 결정
 			LastUpdated:   time.Now(),
 			Metrics: &types.Metrics{
 				CPUUsage:    serverInfo.Metrics.CpuUsage,
 				MemoryUsage: serverInfo.Metrics.MemoryUsage,
 				ErrorRate:   serverInfo.Metrics.ErrorRate,
 				Latency:     serverInfo.Metrics.ResponseTime,
-				Score:       serverInfo.Metrics.Score,
+				PushedScore: serverInfo.Metrics.Score,
 				Timestamp:   time.Now(),
 			},
 		}
@@ -52,26 +65,42 @@ func (c *InternalController) HandleOptimalServer(ctx *fiber.Ctx) error {
 		utils.Infof("  - 메모리 사용률: %.2f%%", server.Metrics.MemoryUsage)
 		utils.Infof("  - 에러율: %.2f%%", server.Metrics.ErrorRate)
 		utils.Infof("  - 응답시간: %.2fms", server.Metrics.Latency)
-		utils.Infof("  - 점수: %.2f", server.Metrics.Score)
+		if server.Metrics.PushedScore != nil {
+			utils.Infof("  - 수신 점수: %.2f", *server.Metrics.PushedScore)
+		}
 
 		// 서버 정보 저장 (없으면 추가, 있으면 업데이트)
 		if err := c.serverService.AddServer(server); err != nil {
 			utils.Warnf("서버 정보 저장 실패: %v", err)
 			continue
 		}
+		utils.Infof("[%s] 계산된 점수: %.2f", server.ServerId, server.Metrics.Score)
 	}
 
 	return utils.SendSuccessMessage(ctx, "최적 서버 등록 완료")
 }
 
 func (c *InternalController) HandleAnalysis(ctx *fiber.Ctx) error {
+	if !utils.AllowsAnalysis(ctx) {
+		utils.Warnf("분석결과 전달 권한 없음 (호출자: %s)", utils.IngestCallerId(ctx))
+		return utils.SendError(ctx, fiber.StatusForbidden, "분석결과를 전달할 권한이 없습니다")
+	}
 	var result types.AnalysisResult
 	if err := ctx.BodyParser(&result); err != nil {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "Invalid payload")
 	}
 	utils.Infof("\n=== AnalyzeCycle 분석결과 api서버에서 수신 ===\n%+v\n", result)
 
+	// 백엔드가 검색 요청에서 받은 traceparent를 돌려주면 원래 검색 트레이스에 이어서 기록
+	spanCtx, span := utils.StartSpan(utils.ExtractTraceContext(ctx.UserContext(), &ctx.Request().Header), "analysis.receive",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("ndns.req_id", result.ReqId),
+			attribute.String("ndns.job_id", result.JobId),
+		))
+	defer span.End()
+
 	jsonMsg, _ := json.Marshal(result)
-	utils.Global.Send(result.ReqId, string(jsonMsg))
+	utils.Global.Send(spanCtx, result.ReqId, string(jsonMsg))
 	return utils.SendSuccessMessage(ctx, "분석결과 수신 완료")
 }
@@ -1,46 +1,42 @@
 package controllers
 
 import (
-	"time"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sh5080/ndns-router/pkg/interfaces"
 	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/types/dtos"
 	"github.com/sh5080/ndns-router/pkg/utils"
 )
 
 // MetricsController는 /api/metrics 경로의 요청을 처리하는 컨트롤러입니다
 type MetricsController struct {
-	serverService interfaces.ServerService
+	serverService  interfaces.ServerService
+	historyService interfaces.HistoryService
 }
 
 // NewMetricsController는 새로운 MetricsController를 생성합니다
-func NewMetricsController(serverService interfaces.ServerService) *MetricsController {
+func NewMetricsController(serverService interfaces.ServerService, historyService interfaces.HistoryService) *MetricsController {
 	return &MetricsController{
-		serverService: serverService,
+		serverService:  serverService,
+		historyService: historyService,
 	}
 }
 
-// MetricsUpdateRequest는 메트릭 업데이트 요청 구조체입니다
-type MetricsUpdateRequest struct {
-	AppName       string    `json:"app_name"`
-	ServerURL     string    `json:"server_url"`
-	ServerType    string    `json:"server_type"`
-	CPUUsage      float64   `json:"cpu_usage"`
-	MemoryUsage   float64   `json:"memory_usage"`
-	ErrorRate     float64   `json:"error_rate"`
-	ResponseTime  float64   `json:"response_time"`
-	TotalRequests int64     `json:"total_requests"`
-	ErrorRequests int64     `json:"error_requests"`
-	Timestamp     time.Time `json:"timestamp"`
-}
-
 // HandleMetricsUpdate는 서버 메트릭 업데이트를 처리합니다
 func (c *MetricsController) HandleMetricsUpdate(ctx *fiber.Ctx) error {
-	var req MetricsUpdateRequest
+	var req dtos.MetricsUpdateRequest
 	if err := ctx.BodyParser(&req); err != nil {
 		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식입니다")
 	}
+	if req.AppName == "" {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "app_name이 필요합니다")
+	}
+	if !utils.AllowsServer(ctx, req.AppName) {
+		utils.Warnf("메트릭 업데이트 권한 없음: %s (호출자: %s)", req.AppName, utils.IngestCallerId(ctx))
+		return utils.SendError(ctx, fiber.StatusForbidden, "서버 정보를 갱신할 권한이 없습니다")
+	}
 
 	// 수신된 메트릭 데이터 로깅
 	utils.Infof("메트릭 수신 [%s]:", req.AppName)
@@ -53,34 +49,25 @@ func (c *MetricsController) HandleMetricsUpdate(ctx *fiber.Ctx) error {
 	utils.Infof("  - 에러 요청수: %d", req.ErrorRequests)
 	utils.Infof("  - 타임스탬프: %s", req.Timestamp)
 
-	// 서버가 없으면 자동으로 등록
-	server, err := c.serverService.GetServer(req.AppName)
-	if err != nil || server == nil {
-		if err := c.serverService.AddServer(&types.Server{
-			ServerId:      req.AppName,
-			ServerUrl:     req.ServerURL,
-			ServerType:    req.ServerType,
-			CurrentStatus: string(types.StatusUnknown),
-			LastUpdated:   time.Now(),
-		}); err != nil {
-			utils.Errorf("서버 자동 등록 실패 (%s): %v", req.AppName, err)
-			return utils.SendError(ctx, fiber.StatusInternalServerError, "서버 등록 실패")
+	// 기존 서버 정보에 메트릭 병합 (서버가 없으면 자동으로 등록)
+	server, err := c.serverService.UpdateMetrics(&types.Server{
+		ServerId:   req.AppName,
+		ServerUrl:  req.ServerURL,
+		ServerType: req.ServerType,
+		Zone:       req.Zone,
+		Region:     req.Region,
+		Labels:     req.Labels,
+	}, req.Report())
+	if err != nil {
+		if errors.Is(err, types.ErrServerDraining) {
+			return utils.SendError(ctx, fiber.StatusConflict, "드레인 중인 서버입니다")
 		}
-		utils.Infof("새 서버가 자동 등록됨: %s (%s)", req.AppName, req.ServerURL)
-	}
-
-	server = &types.Server{
-		ServerId:      req.AppName,
-		ServerUrl:     req.ServerURL,
-		ServerType:    req.ServerType,
-		CurrentStatus: string(types.StatusUnknown),
-		LastUpdated:   time.Now(),
-	}
-
-	if err := c.serverService.AddServer(server); err != nil {
 		utils.Errorf("메트릭 업데이트 실패 (%s): %v", req.AppName, err)
 		return utils.SendError(ctx, fiber.StatusInternalServerError, "메트릭 업데이트 실패")
 	}
 
+	// 점수가 반영된 병합 메트릭을 이력에 기록
+	c.historyService.RecordMetrics(server.ServerId, server.Metrics)
+
 	return utils.SendSuccessMessage(ctx, "메트릭이 성공적으로 업데이트되었습니다")
 }
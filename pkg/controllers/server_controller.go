@@ -1,30 +1,53 @@
 package controllers
 
 import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sh5080/ndns-router/pkg/interfaces"
 	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/types/dtos"
 	"github.com/sh5080/ndns-router/pkg/utils"
+	"github.com/valyala/fasthttp"
 )
 
 // ServerController는 /api/servers 경로의 요청을 처리하는 컨트롤러입니다
 type ServerController struct {
 	serverService interfaces.ServerService
+	zoneService   interfaces.ZoneService
+	eventBus      interfaces.EventBus
+	discovery     interfaces.DiscoveryService
+	history       interfaces.HistoryService
+	scrape        interfaces.ScrapeService
 }
 
 // NewServerController는 새로운 ServerController를 생성합니다
-func NewServerController(serverService interfaces.ServerService) *ServerController {
+func NewServerController(serverService interfaces.ServerService, zoneService interfaces.ZoneService, eventBus interfaces.EventBus, discovery interfaces.DiscoveryService, history interfaces.HistoryService, scrape interfaces.ScrapeService) *ServerController {
 	return &ServerController{
 		serverService: serverService,
+		zoneService:   zoneService,
+		eventBus:      eventBus,
+		discovery:     discovery,
+		history:       history,
+		scrape:        scrape,
 	}
 }
 
 // HandleServersStatus는 등록된 서버 목록과 상태를 반환합니다
 func (c *ServerController) HandleServersStatus(ctx *fiber.Ctx) error {
+	// 라벨 셀렉터 파싱 (예: ?selector=provider=cloudrun,capacity!=small)
+	selector, err := utils.ParseLabelSelector(ctx.Query("selector"))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 셀렉터: "+err.Error())
+	}
+
 	// 서버 목록 조회
-	servers, err := c.serverService.GetAllServers()
+	servers, err := c.serverService.GetServersBySelector(selector)
 	if err != nil {
 		utils.Errorf("서버 목록 조회 실패: %v", err)
 		return utils.SendError(ctx, fiber.StatusInternalServerError, "서버 목록 조회 실패")
@@ -37,6 +60,9 @@ func (c *ServerController) HandleServersStatus(ctx *fiber.Ctx) error {
 			"serverId":    server.ServerId,
 			"serverUrl":   server.ServerUrl,
 			"serverType":  server.ServerType,
+			"zone":        server.Zone,
+			"region":      server.Region,
+			"labels":      server.Labels,
 			"status":      server.CurrentStatus,
 			"lastUpdated": server.LastUpdated.Format(time.RFC3339),
 		}
@@ -45,6 +71,10 @@ func (c *ServerController) HandleServersStatus(ctx *fiber.Ctx) error {
 			serverInfo["metrics"] = server.Metrics
 		}
 
+		if drain, err := c.serverService.GetDrainStatus(server.ServerId); err == nil {
+			serverInfo["drain"] = drain
+		}
+
 		serverInfos = append(serverInfos, serverInfo)
 	}
 
@@ -58,9 +88,12 @@ func (c *ServerController) HandleServersStatus(ctx *fiber.Ctx) error {
 // HandleAddServer는 새로운 서버를 등록합니다
 func (c *ServerController) HandleAddServer(ctx *fiber.Ctx) error {
 	var req struct {
-		ServerId   string `json:"serverId"`
-		URL        string `json:"url"`
-		ServerType string `json:"serverType"`
+		ServerId   string            `json:"serverId"`
+		URL        string            `json:"url"`
+		ServerType string            `json:"serverType"`
+		Zone       string            `json:"zone"`
+		Region     string            `json:"region"`
+		Labels     map[string]string `json:"labels"`
 	}
 
 	if err := ctx.BodyParser(&req); err != nil {
@@ -81,6 +114,9 @@ func (c *ServerController) HandleAddServer(ctx *fiber.Ctx) error {
 		ServerId:      req.ServerId,
 		ServerUrl:     req.URL,
 		ServerType:    req.ServerType,
+		Zone:          req.Zone,
+		Region:        req.Region,
+		Labels:        req.Labels,
 		CurrentStatus: string(types.StatusUnknown),
 		LastUpdated:   time.Now(),
 	}); err != nil {
@@ -103,3 +139,342 @@ func (c *ServerController) HandleRemoveServer(ctx *fiber.Ctx) error {
 
 	return utils.SendSuccessMessage(ctx, "서버가 제거되었습니다")
 }
+
+// HandleScoreDryRun은 서버별 점수가 어떻게 계산되는지 반환합니다 (상태 변경 없음)
+func (c *ServerController) HandleScoreDryRun(ctx *fiber.Ctx) error {
+	breakdowns, err := c.serverService.ExplainScores()
+	if err != nil {
+		utils.Errorf("점수 계산 내역 조회 실패: %v", err)
+		return utils.SendError(ctx, fiber.StatusInternalServerError, "점수 계산 내역 조회 실패")
+	}
+
+	return utils.SendSuccessData(ctx, breakdowns)
+}
+
+// HandleZoneStats는 존별 트래픽과 존 넘김 현황을 반환합니다
+func (c *ServerController) HandleZoneStats(ctx *fiber.Ctx) error {
+	return utils.SendSuccessData(ctx, c.zoneService.GetZoneStats())
+}
+
+// HandleDiscoveryStatus는 디스커버리 제공자별 동기화 현황을 반환합니다
+func (c *ServerController) HandleDiscoveryStatus(ctx *fiber.Ctx) error {
+	return utils.SendSuccessData(ctx, c.discovery.GetStatus())
+}
+
+// HandleScrapeStatus는 서버별 메트릭 수집 현황을 반환합니다
+func (c *ServerController) HandleScrapeStatus(ctx *fiber.Ctx) error {
+	return utils.SendSuccessData(ctx, c.scrape.GetStatus())
+}
+
+// HandleServerHistory는 서버 메트릭 이력을 JSON 또는 CSV로 반환합니다
+// from/to는 RFC3339, 유닉스 초 또는 현재 기준 상대 시간(예: 1h), step은 구간 길이(예: 1m)입니다
+func (c *ServerController) HandleServerHistory(ctx *fiber.Ctx) error {
+	serverId := ctx.Params("id")
+	if server, err := c.serverService.GetServer(serverId); err != nil || server == nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+
+	now := time.Now()
+	from, err := parseHistoryTime(ctx.Query("from"), now, now.Add(-time.Hour))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "from 형식이 올바르지 않습니다")
+	}
+	to, err := parseHistoryTime(ctx.Query("to"), now, now)
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "to 형식이 올바르지 않습니다")
+	}
+	if !from.Before(to) {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "from은 to보다 이전이어야 합니다")
+	}
+
+	var step time.Duration
+	if raw := ctx.Query("step"); raw != "" {
+		step, err = time.ParseDuration(raw)
+		if err != nil || step <= 0 {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "step 형식이 올바르지 않습니다")
+		}
+	}
+
+	points := c.history.Query(serverId, types.HistoryQuery{From: from, To: to, Step: step})
+
+	if ctx.Query("format") == "csv" || strings.Contains(ctx.Get(fiber.HeaderAccept), "text/csv") {
+		return sendHistoryCsv(ctx, points)
+	}
+
+	return utils.SendSuccessData(ctx, fiber.Map{
+		"serverId": serverId,
+		"from":     from,
+		"to":       to,
+		"points":   points,
+	})
+}
+
+// parseHistoryTime 이력 조회 시각 파싱 (비어 있으면 기본값)
+func parseHistoryTime(raw string, now time.Time, fallback time.Time) (time.Time, error) {
+	if raw == "" {
+		return fallback, nil
+	}
+	if ago, err := time.ParseDuration(raw); err == nil {
+		return now.Add(-ago), nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// sendHistoryCsv 메트릭 이력을 CSV로 응답
+func sendHistoryCsv(ctx *fiber.Ctx, points []*types.MetricsPoint) error {
+	ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+
+	writer := csv.NewWriter(ctx)
+	writer.Write([]string{
+		"time", "samples", "cpuUsage", "memoryUsage", "requestRate", "errorRate", "responseTime", "score",
+		"proxyRequests", "proxyErrors", "proxyLatencyMs",
+	})
+	for _, point := range points {
+		writer.Write([]string{
+			point.Time.UTC().Format(time.RFC3339),
+			strconv.Itoa(point.Samples),
+			formatHistoryFloat(point.CPUUsage),
+			formatHistoryFloat(point.MemoryUsage),
+			formatHistoryFloat(point.RequestRate),
+			formatHistoryFloat(point.ErrorRate),
+			formatHistoryFloat(point.Latency),
+			formatHistoryFloat(point.Score),
+			strconv.FormatInt(point.ProxyRequests, 10),
+			strconv.FormatInt(point.ProxyErrors, 10),
+			formatHistoryFloat(point.ProxyLatencyMs),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatHistoryFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// HandleStartDrain은 서버 드레인을 시작합니다
+func (c *ServerController) HandleStartDrain(ctx *fiber.Ctx) error {
+	var req struct {
+		Timeout string            `json:"timeout"` // 예: "30s", "5m" (비어 있으면 기본값)
+		Action  types.DrainAction `json:"action"`  // remove 또는 park (비어 있으면 기본값)
+	}
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식")
+		}
+	}
+
+	var timeout time.Duration
+	if req.Timeout != "" {
+		parsed, err := time.ParseDuration(req.Timeout)
+		if err != nil || parsed <= 0 {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "timeout 형식이 올바르지 않습니다")
+		}
+		timeout = parsed
+	}
+
+	// 서버 ID는 드레인 목록의 키로 저장되므로 fiber 버퍼를 참조하지 않도록 복사
+	status, err := c.serverService.StartDrain(strings.Clone(ctx.Params("id")), timeout, req.Action)
+	if errors.Is(err, types.ErrServerNotFound) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "드레인 시작 실패: "+err.Error())
+	}
+
+	return utils.SendSuccessData(ctx, status)
+}
+
+// HandleCancelDrain은 서버 드레인을 취소하고 라우팅 대상으로 복귀시킵니다
+func (c *ServerController) HandleCancelDrain(ctx *fiber.Ctx) error {
+	err := c.serverService.CancelDrain(ctx.Params("id"))
+	if errors.Is(err, types.ErrNotDraining) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "드레인 중인 서버가 아닙니다")
+	}
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, "드레인 취소 실패: "+err.Error())
+	}
+
+	return utils.SendSuccessMessage(ctx, "드레인이 취소되었습니다")
+}
+
+// HandleDrainStatus는 서버 드레인 진행 상황을 반환합니다
+func (c *ServerController) HandleDrainStatus(ctx *fiber.Ctx) error {
+	status, err := c.serverService.GetDrainStatus(ctx.Params("id"))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, "드레인 중인 서버가 아닙니다")
+	}
+
+	return utils.SendSuccessData(ctx, status)
+}
+
+// HandleServerStatus는 서버 상태 머신 현황과 상태 전이 기록을 반환합니다
+func (c *ServerController) HandleServerStatus(ctx *fiber.Ctx) error {
+	info, err := c.serverService.GetStatusInfo(ctx.Params("id"))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+
+	return utils.SendSuccessData(ctx, info)
+}
+
+// HandleSetStatusOverride는 관리자가 서버 상태를 지정합니다
+func (c *ServerController) HandleSetStatusOverride(ctx *fiber.Ctx) error {
+	var req struct {
+		Status   types.ServerStatus `json:"status"`
+		Reason   string             `json:"reason"`
+		Duration string             `json:"duration"` // 예: "30m" (비어 있으면 해제할 때까지 유지)
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식")
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		parsed, err := time.ParseDuration(req.Duration)
+		if err != nil || parsed <= 0 {
+			return utils.SendError(ctx, fiber.StatusBadRequest, "duration 형식이 올바르지 않습니다")
+		}
+		duration = parsed
+	}
+
+	// 서버 ID는 상태 머신의 키로 저장될 수 있으므로 fiber 버퍼를 참조하지 않도록 복사
+	override, err := c.serverService.SetStatusOverride(strings.Clone(ctx.Params("id")), req.Status, req.Reason, duration)
+	if errors.Is(err, types.ErrServerNotFound) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "상태 지정 실패: "+err.Error())
+	}
+
+	return utils.SendSuccessData(ctx, override)
+}
+
+// HandleClearStatusOverride는 관리자 상태 지정을 해제합니다
+func (c *ServerController) HandleClearStatusOverride(ctx *fiber.Ctx) error {
+	err := c.serverService.ClearStatusOverride(ctx.Params("id"))
+	if errors.Is(err, types.ErrServerNotFound) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+	if errors.Is(err, types.ErrNoStatusOverride) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "관리자가 지정한 상태가 없습니다")
+	}
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusInternalServerError, "상태 지정 해제 실패: "+err.Error())
+	}
+
+	return utils.SendSuccessMessage(ctx, "상태 지정이 해제되었습니다")
+}
+
+// HandleDrainStatuses는 전체 드레인 진행 상황을 반환합니다
+func (c *ServerController) HandleDrainStatuses(ctx *fiber.Ctx) error {
+	return utils.SendSuccessData(ctx, c.serverService.GetDrainStatuses())
+}
+
+// HandleReplaceLabels는 서버 라벨을 전체 교체합니다
+func (c *ServerController) HandleReplaceLabels(ctx *fiber.Ctx) error {
+	var req struct {
+		Labels map[string]string `json:"labels"`
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식")
+	}
+
+	return c.updateLabels(ctx, req.Labels, nil, true)
+}
+
+// HandlePatchLabels는 서버 라벨을 추가/변경하거나 삭제합니다
+func (c *ServerController) HandlePatchLabels(ctx *fiber.Ctx) error {
+	var req struct {
+		Set    map[string]string `json:"set"`
+		Remove []string          `json:"remove"`
+	}
+	if err := ctx.BodyParser(&req); err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 요청 형식")
+	}
+
+	return c.updateLabels(ctx, req.Set, req.Remove, false)
+}
+
+func (c *ServerController) updateLabels(ctx *fiber.Ctx, set map[string]string, remove []string, replace bool) error {
+	server, err := c.serverService.UpdateLabels(ctx.Params("id"), set, remove, replace)
+	if errors.Is(err, types.ErrServerNotFound) {
+		return utils.SendError(ctx, fiber.StatusNotFound, "서버를 찾을 수 없습니다")
+	}
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "라벨 변경 실패: "+err.Error())
+	}
+
+	return utils.SendSuccessData(ctx, server)
+}
+
+// HandleEventStream은 레지스트리 이벤트를 SSE로 전달합니다
+// 필터: ?types=added,removed&serverId=ndns-api1&selector=provider=ec2
+func (c *ServerController) HandleEventStream(ctx *fiber.Ctx) error {
+	// 필터는 요청이 끝난 뒤에도 사용되므로 fiber 버퍼를 참조하지 않도록 복사
+	filter := types.EventFilter{}
+	for _, eventType := range strings.Split(strings.Clone(ctx.Query("types")), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			filter.Types = append(filter.Types, types.RegistryEventType(eventType))
+		}
+	}
+	for _, serverId := range strings.Split(strings.Clone(ctx.Query("serverId")), ",") {
+		if serverId = strings.TrimSpace(serverId); serverId != "" {
+			filter.ServerIds = append(filter.ServerIds, serverId)
+		}
+	}
+	selector, err := utils.ParseLabelSelector(strings.Clone(ctx.Query("selector")))
+	if err != nil {
+		return utils.SendError(ctx, fiber.StatusBadRequest, "잘못된 셀렉터: "+err.Error())
+	}
+	filter.Selector = selector
+
+	ctx.Set("Content-Type", "text/event-stream")
+	ctx.Set("Cache-Control", "no-cache")
+	ctx.Set("Connection", "keep-alive")
+	ctx.Context().SetConnectionClose()
+
+	events, unsubscribe := c.eventBus.Subscribe(filter, 0)
+	utils.Infof("[EVENT] 관리자 이벤트 스트림 연결 (%s)", ctx.IP())
+
+	ctx.Context().Response.SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer func() {
+			unsubscribe()
+			utils.Info("[EVENT] 관리자 이벤트 스트림 종료")
+		}()
+
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := utils.SendSseEvent(w, &dtos.SsePayload{Type: dtos.SseRegistry, Data: event}); err != nil {
+					return
+				}
+			case <-ticker.C:
+				ssePayload := dtos.SsePayload{
+					Type: dtos.SseHeartbeat,
+					Data: map[string]interface{}{
+						"heartbeat": time.Now().Format(time.RFC3339),
+					},
+				}
+				if err := utils.SendSseEvent(w, &ssePayload); err != nil {
+					return
+				}
+			}
+		}
+	}))
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/sh5080/ndns-router/pkg/types"
 )
 
@@ -14,6 +17,135 @@ type ServerService interface {
 	GetServer(serverId string) (*types.Server, error)
 	GetServerGroup() *types.ServerGroup
 	GetServerlessServer() *types.Server
+
+	// UpdateMetrics는 푸시된 메트릭을 기존 서버 정보에 병합하고 갱신된 서버를 반환합니다
+	UpdateMetrics(server *types.Server, report *types.MetricsReport) (*types.Server, error)
+
+	// MergeServer는 서버 정의(URL, 종류, 위치, 라벨)만 갱신하고 메트릭과 상태는 유지합니다
+	MergeServer(server *types.Server) error
+
+	// 라벨 관리
+	UpdateLabels(serverId string, set map[string]string, remove []string, replace bool) (*types.Server, error)
+	GetServersBySelector(selector *types.LabelSelector) ([]*types.Server, error)
+
+	// 요청 추적
+	BeginRequest(serverId string)
+	EndRequest(serverId string)
+	GetActiveRequests(serverId string) int
+
+	// 드레인 관리
+	StartDrain(serverId string, timeout time.Duration, action types.DrainAction) (*types.DrainStatus, error)
+	CancelDrain(serverId string) error
+	GetDrainStatus(serverId string) (*types.DrainStatus, error)
+	GetDrainStatuses() []*types.DrainStatus
+
+	// 상태 머신 입력 (헬스 체크, 프록시 결과, 관리자 지정)
+	ReportProbe(serverId string, healthy bool, reason string) error
+	ReportProxyResult(serverId string, err error)
+	MarkStale(serverId string, reason string) error
+	SetStatusOverride(serverId string, status types.ServerStatus, reason string, duration time.Duration) (*types.StatusOverride, error)
+	ClearStatusOverride(serverId string) error
+	GetStatusInfo(serverId string) (*types.ServerStatusInfo, error)
+
+	// 점수 계산 내역 (서버 상태를 변경하지 않음)
+	ExplainScores() ([]*types.ScoreBreakdown, error)
+
+	// Close는 드레인, 상태 점검 고루틴을 멈춥니다 (여러 번 호출해도 안전)
+	Close() error
+}
+
+// ScoreService 원본 메트릭으로 서버 점수를 계산하는 서비스 인터페이스
+type ScoreService interface {
+	// Calculate는 메트릭으로 점수를 계산하고 계산 내역을 반환합니다
+	Calculate(metrics *types.Metrics) *types.ScoreBreakdown
+	// Apply는 서버 메트릭의 점수를 계산된 값으로 갱신합니다
+	Apply(server *types.Server) *types.ScoreBreakdown
+}
+
+// SseBroker SSE 메시지를 모든 라우터 인스턴스에 전달하는 발행/구독 통로
+// 분석 결과를 받은 인스턴스가 발행하면 각 인스턴스가 자신에게 연결된 구독자에게 전달합니다
+type SseBroker interface {
+	// Publish는 메시지를 자신을 포함한 모든 인스턴스에 발행합니다
+	Publish(ctx context.Context, message *types.SseBrokerMessage) error
+	// Subscribe는 발행된 메시지마다 handler를 호출합니다 (ctx가 끝나면 구독 해지)
+	Subscribe(ctx context.Context, handler func(*types.SseBrokerMessage)) error
+	Close() error
+}
+
+// EventBus 레지스트리 이벤트를 구독자에게 전달하는 인프로세스 이벤트 버스
+type EventBus interface {
+	// Publish는 이벤트를 조건이 맞는 구독자에게 전달합니다 (구독자가 느려도 막히지 않음)
+	Publish(event types.RegistryEvent)
+	// Subscribe는 조건에 맞는 이벤트 채널과 구독 해지 함수를 반환합니다
+	Subscribe(filter types.EventFilter, buffer int) (<-chan types.RegistryEvent, func())
+	// SubscribeFunc는 조건에 맞는 이벤트마다 handler를 호출합니다
+	SubscribeFunc(filter types.EventFilter, handler func(types.RegistryEvent)) func()
+}
+
+// DiscoveryProvider 원하는 서버 목록을 레지스트리에 공급하는 디스커버리 제공자
+type DiscoveryProvider interface {
+	Name() string
+	// Run은 원하는 서버 목록이 바뀔 때마다 updates로 전체 목록을 보내며 ctx가 끝날 때까지 실행합니다
+	Run(ctx context.Context, updates chan<- []*types.Server) error
+}
+
+// DiscoveryService 디스커버리 제공자의 서버 목록을 레지스트리에 반영하는 서비스
+type DiscoveryService interface {
+	Start(ctx context.Context)
+	GetStatus() []*types.DiscoveryStatus
+}
+
+// ScrapeService 서버의 메트릭 경로를 주기적으로 조회해 레지스트리에 반영하는 서비스
+type ScrapeService interface {
+	Start(ctx context.Context)
+	GetStatus() []*types.ScrapeStatus
+}
+
+// ProbeService 서버 헬스 체크 결과를 상태 머신에 전달하는 서비스 인터페이스
+type ProbeService interface {
+	Start(ctx context.Context)
+}
+
+// IngestAuthService 수신 경로(/internal, /metrics) 호출자를 인증하는 서비스
+type IngestAuthService interface {
+	// Enabled는 인증 정보가 설정되어 인증이 필요한지 여부를 반환합니다
+	Enabled() bool
+	// Authenticate는 HMAC 서명 또는 Bearer 토큰을 확인하고 호출자 인증 정보를 반환합니다
+	Authenticate(request *types.IngestRequest) (*types.IngestCredential, error)
+}
+
+// AccessLogger 프록시 요청마다 구조화된 접근 로그를 기록하는 인터페이스
+type AccessLogger interface {
+	Log(record *types.AccessLogRecord)
+	Dropped() uint64
+}
+
+// ServerStore 서버 레지스트리 영속화를 위한 저장소 인터페이스
+type ServerStore interface {
+	Save(server *types.Server) error
+	Delete(serverId string) error
+	LoadAll() ([]*types.Server, error)
+	Close() error
+}
+
+// HistoryService 서버별 메트릭 이력을 보관하는 서비스 인터페이스
+type HistoryService interface {
+	// RecordMetrics는 서버가 보낸 메트릭을 이력에 기록합니다
+	RecordMetrics(serverId string, metrics *types.Metrics)
+	// ObserveRequest는 라우터가 관측한 프록시 요청 결과를 이력에 기록합니다
+	ObserveRequest(serverId string, latency time.Duration, failed bool)
+	// Query는 조회 구간을 step 단위로 묶은 이력을 반환합니다
+	Query(serverId string, query types.HistoryQuery) []*types.MetricsPoint
+}
+
+// ZoneService 존 우선 라우팅을 위한 서비스 인터페이스
+type ZoneService interface {
+	// Select는 같은 존 서버를 우선하는 후보 목록을 반환합니다
+	Select(group *types.ServerGroup) *types.ZoneDecision
+	// RecordTraffic은 선택된 서버의 존별 요청 수를 기록하고 실제 넘김 이유를 반환합니다
+	// (같은 존 서버가 선택되었으면 SpillNone)
+	RecordTraffic(server *types.Server, spill types.SpillReason) types.SpillReason
+	GetZoneStats() *types.ZoneStats
 }
 
 // RouterService는 라우터 서비스 인터페이스입니다
@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"runtime"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -29,6 +30,22 @@ func main() {
 	// 시스템 정보 출력
 	printSystemInfo()
 
+	// 트레이스 설정 (TRACING_EXPORTER)
+	shutdownTracing, err := utils.InitTracing(context.Background())
+	if err != nil {
+		utils.Fatalf("트레이스 설정 실패: %v", err)
+	}
+
+	// StatsD 전송 설정 (STATSD_ADDR)
+	if err := utils.InitStatsd(); err != nil {
+		utils.Fatalf("StatsD 설정 실패: %v", err)
+	}
+
+	// SSE 결과 스트림 설정
+	if err := utils.InitSse(); err != nil {
+		utils.Fatalf("SSE 설정 실패: %v", err)
+	}
+
 	// Fiber 앱 설정
 	app := fiber.New(fiber.Config{
 		AppName:        "NDNS Router",
@@ -38,7 +55,10 @@ func main() {
 		ReadBufferSize: 16384,            // 16KB
 		JSONDecoder:    json.Unmarshal,
 	})
-	app.Use(logger.New())
+	app.Use(logger.New(logger.Config{
+		// 프록시 미들웨어가 저장한 요청 ID를 함께 기록
+		Format: "${time} | [${locals:" + configs.RequestIdLocalKey + "}] | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
+	}))
 
 	// 라우터 설정
 	if err := routers.SetupRoutes(app); err != nil {
@@ -72,6 +92,18 @@ func main() {
 	if err := app.Shutdown(); err != nil {
 		utils.Errorf("서버 종료 실패: %v", err)
 	}
+
+	// 남은 스팬 내보내기
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		utils.Errorf("트레이스 종료 실패: %v", err)
+	}
+
+	// 남은 StatsD 메트릭 전송
+	if err := utils.Statsd.Close(); err != nil {
+		utils.Errorf("StatsD 종료 실패: %v", err)
+	}
 }
 
 // 시스템 정보 출력
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// IngestAuthMiddleware는 /internal, /metrics, POST /external/stream 수신 요청의 HMAC 서명 또는 Bearer 토큰을 확인합니다
+// 서버별 권한 확인은 요청 본문을 해석하는 컨트롤러에서 Locals의 인증 정보로 처리합니다
+func IngestAuthMiddleware(auth interfaces.IngestAuthService) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		// INGEST_AUTH_DISABLED로 인증을 끈 경우에만 인증 없이 허용
+		if !auth.Enabled() {
+			ctx.Locals(configs.IngestCredentialLocalKey, types.AnonymousIngestCredential)
+			return ctx.Next()
+		}
+
+		credential, err := auth.Authenticate(&types.IngestRequest{
+			Method:        ctx.Method(),
+			Uri:           string(ctx.Request().RequestURI()),
+			Body:          ctx.Body(),
+			Authorization: ctx.Get(fiber.HeaderAuthorization),
+			KeyId:         ctx.Get(configs.IngestKeyIdHeader),
+			Timestamp:     ctx.Get(configs.IngestTimestampHeader),
+			Signature:     ctx.Get(configs.IngestSignatureHeader),
+		})
+		if err != nil {
+			requestId, _ := ctx.Locals(configs.RequestIdLocalKey).(string)
+			utils.Warnf("[%s] 수신 요청 인증 실패: %s %s from %s (%v)", requestId, ctx.Method(), ctx.Path(), ctx.IP(), err)
+
+			message := "Invalid credential"
+			switch {
+			case errors.Is(err, types.ErrMissingCredential):
+				message = "Missing credential"
+			case errors.Is(err, types.ErrSignatureExpired):
+				message = "Signature expired"
+			case errors.Is(err, types.ErrSignatureReplayed):
+				message = "Signature already used"
+			}
+			return utils.SendError(ctx, fiber.StatusUnauthorized, message)
+		}
+
+		ctx.Locals(configs.IngestCredentialLocalKey, credential)
+		return ctx.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/sh5080/ndns-router/pkg/configs"
 	"github.com/sh5080/ndns-router/pkg/utils"
 )
 
@@ -20,11 +21,21 @@ func JwtMiddleware() fiber.Handler {
 			return utils.SendError(ctx, fiber.StatusUnauthorized, "Invalid token format")
 		}
 
-		_, err := utils.ParseAndValidateSseToken(parts[1])
-		if err != nil {
+		claims, err := utils.ParseAndValidateSseToken(parts[1])
+		if err != nil || claims == nil {
 			return utils.SendError(ctx, fiber.StatusUnauthorized, "Invalid or expired token")
 		}
-		ctx.Locals("reqId", ctx.Query("reqId"))
+
+		// 토큰에 담긴 reqId(검색 응답 시 라우터가 발급한 스트림 ID)와 구독 대상이 같아야 함
+		reqId := ctx.Query("reqId")
+		if reqId != "" && reqId != claims.ReqId {
+			utils.Warnf("[%s] SSE 토큰 reqId 불일치 (요청: %s)", claims.ReqId, reqId)
+			return utils.SendError(ctx, fiber.StatusForbidden, "Token does not match reqId")
+		}
+
+		// X-Request-ID는 이 연결의 요청 ID로 유지하고, 토큰을 발급한 요청 ID는 따로 전달
+		ctx.Locals("reqId", claims.ReqId)
+		ctx.Locals(configs.IssuerRequestIdLocalKey, claims.RequestId)
 		return ctx.Next()
 	}
 }
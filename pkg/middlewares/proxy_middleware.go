@@ -2,8 +2,10 @@ package middlewares
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/proxy"
@@ -13,88 +15,102 @@ import (
 	"github.com/sh5080/ndns-router/pkg/types"
 	"github.com/sh5080/ndns-router/pkg/utils"
 	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // selectProxyServer는 요청 Limit 값과 서버 상태에 따라 프록시할 최적의 서버를 선택합니다.
 // 적합한 서버를 찾으면 해당 서버 객체를 반환하고, 그렇지 않으면 nil을 반환합니다.
-func selectProxyServer(c *fiber.Ctx, serverService interfaces.ServerService, requestId string) *types.Server {
+// 같은 존 서버를 우선하며, 다른 존으로 넘긴 경우 그 이유를 함께 반환합니다.
+// 서버리스 강제 사용 비율에 걸리면 서버리스 서버와 함께 forced=true를 반환합니다 (서버가 없어 넘긴 폴백과 구분).
+func selectProxyServer(c *fiber.Ctx, serverService interfaces.ServerService, zoneService interfaces.ZoneService, policy *types.RoutingPolicy, requestId string) (*types.Server, types.SpillReason, bool) {
 	serverGroup := serverService.GetServerGroup()
 	limit := c.QueryInt("limit", 0)
 
 	// limit=2일 때는 서버리스 강제사용 건너뛰기
-	if limit != 2 && serverGroup.ForceServerless {
-		utils.Infof("[%s] 서버리스 강제 사용", requestId)
-		return serverGroup.ServerlessServer
+	if limit != 2 {
+		utils.Prometheus.ObserveServerlessDecision(serverGroup.ForceServerless)
+		utils.Statsd.ObserveServerlessDecision(serverGroup.ForceServerless)
+		if serverGroup.ForceServerless {
+			utils.Infof("[%s] 서버리스 강제 사용", requestId)
+			return serverService.GetServerlessServer(), types.SpillNone, true
+		}
+	}
+
+	// 같은 존 우선 후보 선택
+	decision := zoneService.Select(serverGroup)
+	if decision.CrossRegion {
+		utils.Infof("[%s] 같은 리전 서버 사용 불가, 다른 리전으로 넘김 (이유: %s)", requestId, decision.Spill)
+	} else if decision.Spill != types.SpillNone {
+		utils.Infof("[%s] 같은 존 서버 사용 불가, 다른 존으로 넘김 (이유: %s)", requestId, decision.Spill)
 	}
 
-	// limit 값에 따른 우선순위 정의
-	var performanceOrder []string
+	// limit 값에 따른 우선순위 정의 (라벨 셀렉터 목록, 라우팅 설정에서 지정)
+	var performanceOrder []*types.LabelSelector
 	// limit ~2일 때는 서버리스 사용하지 않음
 	if limit <= 2 {
-		performanceOrder = []string{
-			"ndns-api1",     // EC2
-			"ndns-api2",     // Windows Desktop
-			"ndns-external", // Mac M1
-		}
+		performanceOrder = policy.PriorityLimit2
 		utils.Infof("[%s] Limit=2 우선순위 적용", requestId)
 	} else if limit <= 10 {
-		performanceOrder = []string{
-			"ndns-external", // Mac M1
-			"ndns-api1",     // EC2
-			"ndns-api2",     // Windows Desktop
-			"ndns-api3",     // Cloud Run
-		}
+		performanceOrder = policy.PriorityLimit10
 		utils.Infof("[%s] Limit=10 우선순위 적용", requestId)
 	} else {
 		// 기본 우선순위
-		performanceOrder = []string{
-			"ndns-external", // Mac M1
-			"ndns-api1",     // EC2
-			"ndns-api3",     // Cloud Run
-			"ndns-api2",     // Windows Desktop
-		}
+		performanceOrder = policy.PriorityDefault
 		utils.Infof("[%s] 기본 우선순위 적용", requestId)
 	}
 
 	// Excellent 서버가 있으면 Excellent 서버들 중에서만 선택
-	if len(serverGroup.ExcellentServers) > 0 {
-		for _, preferredId := range performanceOrder {
-			for _, server := range serverGroup.ExcellentServers {
-				if server.ServerId == preferredId {
-					utils.Infof("[%s] Excellent 서버 중 성능 우선순위 선택: %s (점수: %.2f, limit: %d)",
-						requestId, server.ServerId, server.Metrics.Score, limit)
-					return server
+	if len(decision.ExcellentServers) > 0 {
+		for _, preferred := range performanceOrder {
+			for _, server := range decision.ExcellentServers {
+				if preferred.MatchesServer(server) {
+					utils.Infof("[%s] Excellent 서버 중 성능 우선순위 선택: %s (셀렉터: %s, 점수: %.2f, limit: %d)",
+						requestId, server.ServerId, preferred, server.Metrics.Score, limit)
+					return server, decision.Spill, false
 				}
 			}
 		}
-		return serverGroup.ExcellentServers[0]
+		return decision.ExcellentServers[0], decision.Spill, false
 	}
 
 	// Good 서버들 중에서 선택
-	if len(serverGroup.GoodServers) > 0 {
-		for _, preferredId := range performanceOrder {
-			for _, server := range serverGroup.GoodServers {
-				if server.ServerId == preferredId {
-					utils.Infof("[%s] Good 서버 중 성능 우선순위 선택: %s (점수: %.2f, limit: %d)",
-						requestId, server.ServerId, server.Metrics.Score, limit)
-					return server
+	if len(decision.GoodServers) > 0 {
+		for _, preferred := range performanceOrder {
+			for _, server := range decision.GoodServers {
+				if preferred.MatchesServer(server) {
+					utils.Infof("[%s] Good 서버 중 성능 우선순위 선택: %s (셀렉터: %s, 점수: %.2f, limit: %d)",
+						requestId, server.ServerId, preferred, server.Metrics.Score, limit)
+					return server, decision.Spill, false
 				}
 			}
 		}
-		return serverGroup.GoodServers[0]
+		return decision.GoodServers[0], decision.Spill, false
 	}
 
-	return nil
+	return nil, types.SpillNone, false
 }
 
-func NewProxyMiddleware(serverService interfaces.ServerService) fiber.Handler {
+// clientIp 클라이언트 IP (X-Forwarded-For가 없으면 연결 주소)
+func clientIp(c *fiber.Ctx) string {
+	if ip := c.IP(); ip != "" {
+		return ip
+	}
+	return c.Context().RemoteIP().String()
+}
+
+func NewProxyMiddleware(serverService interfaces.ServerService, zoneService interfaces.ZoneService, policy *types.RoutingPolicy, historyService interfaces.HistoryService, accessLogger interfaces.AccessLogger) fiber.Handler {
 	pathUtil := utils.NewPath(configs.InternalPaths)
 
-	// 서버 요청 시도 (tryServer 함수는 그대로 유지)
-	tryServer := func(ctx *fiber.Ctx, server *types.Server, requestId string) error {
+	// 서버 요청 시도 (시도 횟수, 서버, 업스트림 결과는 접근 로그 레코드에 누적)
+	tryServer := func(ctx *fiber.Ctx, server *types.Server, requestId string, record *types.AccessLogRecord) error {
 		if server == nil {
 			utils.Infof("[%s] 서버가 없어 서버리스로 전환", requestId)
 			server = serverService.GetServerlessServer() // 폴백 서버 (서버리스)
+			if record.Fallback == "" {
+				record.Fallback = utils.FallbackNoServer
+			}
 		}
 
 		// nil이 여전히 발생할 수 있는 시나리오 방지
@@ -104,12 +120,26 @@ func NewProxyMiddleware(serverService interfaces.ServerService) fiber.Handler {
 		}
 
 		utils.Infof("[%s] 서버 시도: %s (점수: %.2f)", requestId, server.ServerId, server.Metrics.Score)
+		record.Attempts++
+		record.ServerId = server.ServerId
+		record.ServerType = server.ServerType
+
+		// 업스트림 시도마다 스팬을 만들고 traceparent로 백엔드에 전달
+		spanCtx, span := utils.StartSpan(ctx.UserContext(), "proxy.upstream",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("ndns.server_id", server.ServerId),
+				attribute.Int("ndns.attempt", record.Attempts),
+			))
+		defer span.End()
+		if record.Fallback != "" {
+			span.SetAttributes(attribute.String("ndns.fallback", record.Fallback))
+		}
 
-		// test url
-		server.ServerUrl = configs.GetConfig().App.TestUrl
-		utils.Infof("강제 테스트 url: %s", server.ServerUrl)
+		// test url (레지스트리의 서버 객체는 공유되므로 직접 변경하지 않음)
+		targetURL := configs.GetConfig().App.TestUrl
+		utils.Infof("[%s] 강제 테스트 url: %s", requestId, targetURL)
 		// [1] URL 정규화
-		targetURL := server.ServerUrl
 		if !strings.HasPrefix(targetURL, "http://") && !strings.HasPrefix(targetURL, "https://") {
 			targetURL = "https://" + targetURL
 		}
@@ -126,40 +156,71 @@ func NewProxyMiddleware(serverService interfaces.ServerService) fiber.Handler {
 		ctx.Request().Header.Set("X-Forwarded-Host", string(ctx.Request().Header.Host()))
 		ctx.Request().Header.Set("X-Origin-Host", server.ServerId)
 		ctx.Request().Header.Set("X-App-Name", server.ServerId)
-		ctx.Request().Header.Set("X-Request-ID", requestId)
+		ctx.Request().Header.Set(configs.RequestIdHeader, requestId)
+		utils.InjectTraceContext(spanCtx, &ctx.Request().Header)
 
 		// [4] TLS 검증 건너뛰기 설정 및 프록시 요청 실행
+		serverService.BeginRequest(server.ServerId)
+		startedAt := time.Now()
 		err := proxy.Do(ctx, fullURL, &fasthttp.Client{
 			TLSConfig: &tls.Config{
 				InsecureSkipVerify: true,
 			},
 		})
+		serverService.EndRequest(server.ServerId)
+		latency := time.Since(startedAt)
+		record.UpstreamStatus = 0
+		if err == nil {
+			record.UpstreamStatus = ctx.Response().StatusCode()
+		}
+		record.UpstreamLatencyMs = float64(latency.Microseconds()) / 1000
+		historyService.ObserveRequest(server.ServerId, latency, err != nil || ctx.Response().StatusCode() >= fiber.StatusInternalServerError)
+		utils.Prometheus.ObserveUpstream(server.ServerId, ctx.Response().StatusCode(), err, latency)
+		utils.Statsd.ObserveUpstream(server.ServerId, ctx.Response().StatusCode(), err, latency)
+		// 실패는 상태 머신에 전달되어 기준 횟수를 넘으면 비정상(unhealthy)으로 전환
+		serverService.ReportProxyResult(server.ServerId, err)
+		span.SetAttributes(attribute.Int("http.response.status_code", ctx.Response().StatusCode()))
 		if err != nil {
-			utils.Warnf("[%s] 서버 요청 실패, 서버 제거: %s (%v)", requestId, server.ServerId, err)
-			serverService.RemoveServer(server.ServerId)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "upstream request failed")
+			utils.Warnf("[%s] 서버 요청 실패: %s (%v)", requestId, server.ServerId, err)
 			return err
 		}
 
-		// [6] 응답 헤더에 서버 정보 추가
+		// [6] 응답 헤더에 서버 정보 및 요청 ID 추가 (업스트림 응답 헤더로 덮어쓰이므로 다시 설정)
+		ctx.Response().Header.Set(configs.RequestIdHeader, requestId)
 		ctx.Response().Header.Set("X-Served-By", server.ServerId)
 		ctx.Response().Header.Set("X-Server-Score", fmt.Sprintf("%.2f", server.Metrics.Score))
 		// [7] jwt 허용 경로일 경우 토큰 생성
 		if types.IsJwtEligible(endpoint) {
 			if strings.HasPrefix(endpoint, "/api/v1/search") {
-				if token, err := utils.GenerateSseToken(requestId, 10); err == nil {
-
+				// SSE 스트림 reqId는 항상 라우터가 새로 발급 (클라이언트가 정한 요청 ID로 다른 스트림을 구독할 수 없도록)
+				// 요청 ID와의 연결은 아래 로그와 응답 헤더로 추적
+				sseReqId := uuid.New().String()
+				if token, err := utils.GenerateSseToken(sseReqId, requestId, 10); err == nil {
+					utils.Infof("[%s] SSE 스트림 발급: %s", requestId, sseReqId)
+					utils.Global.Issue(sseReqId)
+					ctx.Response().Header.Set("X-Req-Id", sseReqId)
 					ctx.Response().Header.Set("X-Sse-Token", token)
 					ctx.Response().Header.Set("X-Sse-Id", uuid.New().String())
-					ctx.Response().Header.Set("Access-Control-Expose-Headers", "X-Req-Id, X-Sse-Token, X-Sse-Id")
+					ctx.Response().Header.Set("Access-Control-Expose-Headers", "X-Request-ID, X-Req-Id, X-Sse-Token, X-Sse-Id")
+				} else {
+					utils.Warnf("[%s] SSE 토큰 생성 실패: %v", requestId, err)
 				}
 			}
 		}
 		return nil
 	}
 
-	return func(c *fiber.Ctx) error {
-		// [1] 요청 시작 및 초기화
-		requestId := uuid.New().String()
+	return func(c *fiber.Ctx) (err error) {
+		// [1] 요청 시작 및 초기화 (유효한 X-Request-ID가 있으면 그대로 사용)
+		incomingId := c.Get(configs.RequestIdHeader)
+		requestId, reused := utils.ResolveRequestId(incomingId)
+		if incomingId != "" && !reused {
+			utils.Warnf("[%s] 잘못된 형식의 %s 무시 (길이: %d)", requestId, configs.RequestIdHeader, len(incomingId))
+		}
+		c.Locals(configs.RequestIdLocalKey, requestId)
+		c.Set(configs.RequestIdHeader, requestId)
 		path := c.Path()
 
 		utils.Infof("[%s] 새로운 프록시 요청 시작: %s %s", requestId, c.Method(), path)
@@ -170,23 +231,97 @@ func NewProxyMiddleware(serverService interfaces.ServerService) fiber.Handler {
 			return c.Next()
 		}
 
+		// 프록시 대상 요청마다 접근 로그 한 줄 기록 (차단된 요청 포함)
+		// 레코드는 요청 처리 이후 별도 고루틴에서 기록되므로 요청 버퍼를 참조하는 문자열은 복사본 사용
+		record := &types.AccessLogRecord{
+			Time:      time.Now(),
+			RequestId: requestId,
+			ClientIp:  strings.Clone(clientIp(c)),
+			Method:    strings.Clone(c.Method()),
+			Path:      strings.Clone(path),
+			BytesIn:   len(c.Request().Body()),
+		}
+		defer func() {
+			record.Status = c.Response().StatusCode()
+			if err != nil {
+				// 반환된 오류는 미들웨어 이후 에러 핸들러가 응답하므로 해당 상태 코드 기록
+				record.Status = fiber.StatusInternalServerError
+				var fiberErr *fiber.Error
+				if errors.As(err, &fiberErr) {
+					record.Status = fiberErr.Code
+				}
+			}
+			record.BytesOut = len(c.Response().Body())
+			record.LatencyMs = float64(time.Since(record.Time).Microseconds()) / 1000
+			accessLogger.Log(record)
+		}()
+
 		// [3] API 요청 검증
 		if !strings.HasPrefix(path, "/api") {
 			utils.Warnf("[%s] 비정상 요청 차단: %s %s from %s",
 				requestId, c.Method(), path, c.IP())
+			record.Error = "blocked"
 			return utils.SendError(c, fiber.StatusForbidden, "Forbidden")
 		}
 
 		utils.Infof("[%s] 내부 경로 아님, 프록시 처리 시작", requestId)
 
+		// 요청 전체 스팬 (클라이언트가 보낸 traceparent가 있으면 이어서 기록)
+		// 스팬은 비동기로 내보내므로 fiber 버퍼를 참조하지 않도록 복사한 값만 사용
+		reqCtx, span := utils.StartSpan(utils.ExtractTraceContext(c.UserContext(), &c.Request().Header), "proxy.request",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", strings.Clone(c.Method())),
+				attribute.String("url.path", strings.Clone(path)),
+				attribute.String("ndns.request_id", strings.Clone(requestId)),
+			))
+		defer span.End()
+		c.SetUserContext(reqCtx)
+
 		// 단일 서버 선택 및 요청 시도
-		selectedServer := selectProxyServer(c, serverService, requestId)
-		err := tryServer(c, selectedServer, requestId)
+		_, selectSpan := utils.StartSpan(reqCtx, "proxy.select")
+		selectedServer, spill, forced := selectProxyServer(c, serverService, zoneService, policy, requestId)
+		record.Forced = forced
+		spill = zoneService.RecordTraffic(selectedServer, spill)
+		if selectedServer != nil {
+			selectSpan.SetAttributes(attribute.String("ndns.server_id", selectedServer.ServerId))
+		}
+		if forced {
+			selectSpan.SetAttributes(attribute.Bool("ndns.forced_serverless", true))
+		}
+		if spill != types.SpillNone {
+			selectSpan.SetAttributes(attribute.String("ndns.spill", string(spill)))
+			record.Spill = string(spill)
+		}
+		selectSpan.End()
+
+		selectedServerId := ""
+		if selectedServer != nil {
+			selectedServerId = selectedServer.ServerId
+		}
+		utils.Statsd.ObserveSelection(selectedServerId, string(spill))
+
+		if selectedServer == nil {
+			utils.Prometheus.IncFallback(utils.FallbackNoServer)
+			utils.Statsd.IncFallback(utils.FallbackNoServer)
+		}
+		err = tryServer(c, selectedServer, requestId, record)
 		if err != nil {
 			utils.Infof("[%s] 서버리스로 전환", requestId)
-			return tryServer(c, nil, requestId)
+			utils.Prometheus.IncRetry()
+			utils.Prometheus.IncFallback(utils.FallbackUpstreamError)
+			utils.Statsd.IncRetry()
+			utils.Statsd.IncFallback(utils.FallbackUpstreamError)
+			record.Fallback = utils.FallbackUpstreamError
+			err = tryServer(c, nil, requestId, record)
 		}
 
-		return nil
+		span.SetAttributes(attribute.Int("http.response.status_code", c.Response().StatusCode()))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "proxy failed")
+			record.Error = err.Error()
+		}
+		return err
 	}
 }
@@ -8,7 +8,7 @@ import (
 )
 
 // SetupExternalRoutes는 /external 경로의 라우터를 설정합니다
-func SetupExternalRoutes(router fiber.Router, serverService interfaces.ServerService) error {
+func SetupExternalRoutes(router fiber.Router, serverService interfaces.ServerService, ingestAuth interfaces.IngestAuthService) error {
 	controller := controllers.NewExternalController(serverService)
 	// Sse 연결 라우터
 	stream := router.Group("/stream")
@@ -16,8 +16,8 @@ func SetupExternalRoutes(router fiber.Router, serverService interfaces.ServerSer
 		// Sse 연결
 		stream.Get("/", middlewares.JwtMiddleware(), controller.SseHandler)
 
-		// Sse 전송
-		stream.Post("/", controller.SendMessage)
+		// Sse 전송 (분석 결과 전달 권한이 있는 수신 호출자만 허용)
+		stream.Post("/", middlewares.IngestAuthMiddleware(ingestAuth), controller.SendMessage)
 
 		// Sse 연결 조회
 		stream.Get("/connections", controller.GetActiveConnections)
@@ -1,6 +1,8 @@
 package routers
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sh5080/ndns-router/pkg/middlewares"
 	"github.com/sh5080/ndns-router/pkg/services"
@@ -10,32 +12,116 @@ import (
 // SetupRoutes는 애플리케이션의 모든 라우트를 설정합니다
 func SetupRoutes(app *fiber.App) error {
 	// 서비스 초기화
-	serverService, err := services.NewServerService()
+	scoreService, err := services.NewScoreService()
+	if err != nil {
+		return err
+	}
+
+	serverStore, err := services.NewServerStore()
+	if err != nil {
+		return err
+	}
+
+	// 레지스트리 이벤트 버스 및 감사 로그 구독
+	eventBus := services.NewEventBus()
+	services.StartAuditLog(eventBus)
+
+	serverService, err := services.NewServerService(scoreService, serverStore, eventBus)
+	if err != nil {
+		return err
+	}
+
+	historyService, err := services.NewHistoryService(eventBus)
+	if err != nil {
+		return err
+	}
+
+	zoneService, err := services.NewZoneService(serverService)
+	if err != nil {
+		return err
+	}
+
+	routingPolicy, err := services.NewRoutingPolicy()
+	if err != nil {
+		return err
+	}
+
+	// 프록시 접근 로그 (ACCESS_LOG_*)
+	accessLogger, err := services.NewAccessLogger()
+	if err != nil {
+		return err
+	}
+
+	// 수신 경로 인증 (INGEST_CREDENTIALS_FILE)
+	ingestAuth, err := services.NewIngestAuthService()
+	if err != nil {
+		return err
+	}
+
+	// 서비스 디스커버리 (SERVER_LIST, DISCOVERY_FILE)
+	providers, err := services.NewDiscoveryProviders()
+	if err != nil {
+		return err
+	}
+
+	discoveryService, err := services.NewDiscoveryService(serverService, providers...)
+	if err != nil {
+		return err
+	}
+	discoveryService.Start(context.Background())
+
+	// 메트릭 수집 (SCRAPE_SELECTOR와 일치하는 서버는 라우터가 직접 조회)
+	scrapeService, err := services.NewScrapeService(serverService, historyService)
 	if err != nil {
 		return err
 	}
+	scrapeService.Start(context.Background())
+
+	// 헬스 체크 (STATUS_PROBE_PATH가 있으면 모든 서버를 주기적으로 조회)
+	probeService, err := services.NewProbeService(serverService)
+	if err != nil {
+		return err
+	}
+	probeService.Start(context.Background())
+
+	// Prometheus 노출 경로는 프록시 대상이 아니므로 프록시 미들웨어보다 먼저 등록
+	if err := SetupPrometheusRoute(app, serverService); err != nil {
+		return err
+	}
 
 	// 프록시 미들웨어를 먼저 설정 (모든 요청에 대해 먼저 검사)
-	app.Use(middlewares.NewProxyMiddleware(serverService))
+	app.Use(middlewares.NewProxyMiddleware(serverService, zoneService, routingPolicy, historyService, accessLogger))
 
 	// 내부 관리용 라우터 설정
 	servers := app.Group("/servers")
-	if err := SetupServerRoutes(servers, serverService); err != nil {
+	if err := SetupServerRoutes(servers, serverService, zoneService, eventBus, discoveryService, historyService, scrapeService); err != nil {
 		return err
 	}
 
-	metrics := app.Group("/metrics")
-	if err := SetupMetricsRoutes(metrics, serverService); err != nil {
+	metrics := app.Group("/metrics", middlewares.IngestAuthMiddleware(ingestAuth))
+	if err := SetupMetricsRoutes(metrics, serverService, historyService); err != nil {
 		return err
 	}
 
-	internal := app.Group("/internal")
+	internal := app.Group("/internal", middlewares.IngestAuthMiddleware(ingestAuth))
 	if err := SetupInternalRoutes(internal, serverService); err != nil {
 		return err
 	}
 
+	// SSE 메시지 발행/구독 통로 (SSE_BROKER, 여러 인스턴스가 같은 통로로 결과를 주고받음)
+	sseBroker, err := services.NewSseBroker()
+	if err != nil {
+		return err
+	}
+	if err := utils.Global.UseBroker(context.Background(), sseBroker); err != nil {
+		return err
+	}
+
+	// 만료/유휴 SSE 연결 정리
+	utils.Global.StartReaper(context.Background())
+
 	external := app.Group("/external")
-	if err := SetupExternalRoutes(external, serverService); err != nil {
+	if err := SetupExternalRoutes(external, serverService, ingestAuth); err != nil {
 		return err
 	}
 
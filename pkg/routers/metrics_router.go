@@ -7,8 +7,8 @@ import (
 )
 
 // SetupMetricsRoutes는 /api/metrics 경로의 라우터를 설정합니다
-func SetupMetricsRoutes(router fiber.Router, serverService interfaces.ServerService) error {
-	controller := controllers.NewMetricsController(serverService)
+func SetupMetricsRoutes(router fiber.Router, serverService interfaces.ServerService, historyService interfaces.HistoryService) error {
+	controller := controllers.NewMetricsController(serverService, historyService)
 	{
 		// 메트릭 업데이트
 		router.Post("/update", controller.HandleMetricsUpdate)
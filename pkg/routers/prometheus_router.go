@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/services"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// SetupPrometheusRoute는 Prometheus 텍스트 형식 메트릭 경로를 설정합니다
+func SetupPrometheusRoute(app *fiber.App, serverService interfaces.ServerService) error {
+	config := configs.GetConfig().Prometheus
+	if !config.Enabled {
+		return nil
+	}
+
+	path := config.Path
+	if !strings.HasPrefix(path, "/") || path == "/" {
+		return fmt.Errorf("잘못된 PROMETHEUS_PATH: %q", path)
+	}
+	if utils.NewPath(configs.InternalPaths).IsInternalPath(path) {
+		return fmt.Errorf("PROMETHEUS_PATH가 내부 관리 경로와 겹칩니다: %s", path)
+	}
+
+	// 수집 시점의 서버 레지스트리 상태 게이지
+	if err := utils.Prometheus.Registry.Register(services.NewRegistryCollector(serverService)); err != nil {
+		return err
+	}
+
+	handler := utils.Prometheus.Handler()
+	app.Get(path, func(ctx *fiber.Ctx) error {
+		handler(ctx.Context())
+		return nil
+	})
+
+	utils.Infof("Prometheus 메트릭 경로: %s", path)
+	return nil
+}
@@ -7,16 +7,41 @@ import (
 )
 
 // SetupServerRoutes는 /api/servers 경로의 라우터를 설정합니다
-func SetupServerRoutes(router fiber.Router, serverService interfaces.ServerService) error {
-	controller := controllers.NewServerController(serverService)
+func SetupServerRoutes(router fiber.Router, serverService interfaces.ServerService, zoneService interfaces.ZoneService, eventBus interfaces.EventBus, discovery interfaces.DiscoveryService, history interfaces.HistoryService, scrape interfaces.ScrapeService) error {
+	controller := controllers.NewServerController(serverService, zoneService, eventBus, discovery, history, scrape)
 
 	{
-		// 서버 상태 목록 조회
+		// 서버 상태 목록 조회 (?selector=로 라벨 필터링)
 		router.Get("/", controller.HandleServersStatus)
 		// 서버 추가
 		router.Post("/add", controller.HandleAddServer)
 		// 서버 제거
 		router.Delete("/remove", controller.HandleRemoveServer)
+		// 점수 계산 내역 조회 (dry-run)
+		router.Get("/scores/dry-run", controller.HandleScoreDryRun)
+		// 존별 트래픽 현황 조회
+		router.Get("/zones", controller.HandleZoneStats)
+		// 디스커버리 동기화 현황 조회
+		router.Get("/discovery", controller.HandleDiscoveryStatus)
+		// 메트릭 수집 현황 조회
+		router.Get("/scrape", controller.HandleScrapeStatus)
+		// 레지스트리 이벤트 스트림 (SSE)
+		router.Get("/events", controller.HandleEventStream)
+		// 드레인 현황 조회
+		router.Get("/drains", controller.HandleDrainStatuses)
+		// 서버 라벨 교체 / 부분 변경
+		router.Put("/:id/labels", controller.HandleReplaceLabels)
+		router.Patch("/:id/labels", controller.HandlePatchLabels)
+		// 서버 상태 머신 현황 조회 / 관리자 상태 지정 / 해제
+		router.Get("/:id/status", controller.HandleServerStatus)
+		router.Put("/:id/status", controller.HandleSetStatusOverride)
+		router.Delete("/:id/status", controller.HandleClearStatusOverride)
+		// 서버 메트릭 이력 조회 (?from=&to=&step=&format=csv)
+		router.Get("/:id/history", controller.HandleServerHistory)
+		// 서버 드레인 시작 / 취소 / 진행 상황 조회
+		router.Post("/:id/drain", controller.HandleStartDrain)
+		router.Delete("/:id/drain", controller.HandleCancelDrain)
+		router.Get("/:id/drain", controller.HandleDrainStatus)
 	}
 
 	return nil
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// 접근 로그 출력 대상
+const (
+	accessLogOutputStdout = "stdout"
+	accessLogOutputFile   = "file"
+)
+
+// 큐가 가득 찼을 때 경고 로그를 남기는 유실 건수 간격
+const accessLogDropWarnInterval = 1000
+
+// 접근 로그 형식
+const (
+	accessLogFormatJson     = "json"
+	accessLogFormatTemplate = "template"
+)
+
+// accessLoggerImpl implements the AccessLogger interface
+type accessLoggerImpl struct {
+	enabled    bool
+	tmpl       *template.Template // 비어 있으면 JSON
+	sampleRate float64            // 성공한 요청 기록 비율
+
+	writer  io.Writer
+	records chan *types.AccessLogRecord // 기록 대기 큐 (가득 차면 버림)
+	dropped atomic.Uint64
+}
+
+// NewAccessLogger creates a new instance of AccessLogger
+func NewAccessLogger() (interfaces.AccessLogger, error) {
+	cfg := configs.GetConfig().AccessLog
+	if !cfg.Enabled {
+		return &accessLoggerImpl{}, nil
+	}
+
+	if cfg.SuccessSampleRate < 0 || cfg.SuccessSampleRate > 1 {
+		return nil, fmt.Errorf("ACCESS_LOG_SUCCESS_SAMPLE_RATE는 0~1 사이여야 합니다: %v", cfg.SuccessSampleRate)
+	}
+	if cfg.QueueSize <= 0 {
+		return nil, fmt.Errorf("ACCESS_LOG_QUEUE_SIZE는 0보다 커야 합니다: %d", cfg.QueueSize)
+	}
+
+	var tmpl *template.Template
+	switch strings.ToLower(cfg.Format) {
+	case accessLogFormatJson:
+	case accessLogFormatTemplate:
+		parsed, err := template.New("access_log").Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("ACCESS_LOG_TEMPLATE 파싱 실패: %w", err)
+		}
+		tmpl = parsed
+	default:
+		return nil, fmt.Errorf("알 수 없는 ACCESS_LOG_FORMAT: %s", cfg.Format)
+	}
+
+	writers := make([]io.Writer, 0, 2)
+	for _, output := range cfg.Outputs {
+		switch strings.ToLower(strings.TrimSpace(output)) {
+		case accessLogOutputStdout:
+			writers = append(writers, os.Stdout)
+		case accessLogOutputFile:
+			if dir := filepath.Dir(cfg.FilePath); dir != "" {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return nil, fmt.Errorf("접근 로그 디렉토리 생성 실패: %w", err)
+				}
+			}
+			file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return nil, fmt.Errorf("접근 로그 파일 열기 실패: %w", err)
+			}
+			writers = append(writers, file)
+		default:
+			return nil, fmt.Errorf("알 수 없는 ACCESS_LOG_OUTPUT: %s", output)
+		}
+	}
+	if len(writers) == 0 {
+		return &accessLoggerImpl{}, nil
+	}
+
+	utils.Infof("접근 로그 설정 완료 (형식: %s, 출력: %s, 성공 요청 기록 비율: %.2f, 큐 크기: %d)",
+		cfg.Format, strings.Join(cfg.Outputs, ","), cfg.SuccessSampleRate, cfg.QueueSize)
+
+	logger := &accessLoggerImpl{
+		enabled:    true,
+		tmpl:       tmpl,
+		sampleRate: cfg.SuccessSampleRate,
+		writer:     io.MultiWriter(writers...),
+		records:    make(chan *types.AccessLogRecord, cfg.QueueSize),
+	}
+	go logger.run()
+
+	return logger, nil
+}
+
+// Log 접근 로그 한 줄을 기록 큐에 넣음 (성공한 요청은 샘플링, 실패/폴백 요청은 항상 기록)
+// 요청 처리 경로에서 기록을 기다리지 않도록 큐가 가득 차면 버리고 유실 건수만 셈
+// 큐에 넣은 레코드는 이후 수정하면 안 됩니다
+func (l *accessLoggerImpl) Log(record *types.AccessLogRecord) {
+	if !l.enabled {
+		return
+	}
+
+	if record.Succeeded() && l.sampleRate < 1 && rand.Float64() >= l.sampleRate {
+		return
+	}
+
+	select {
+	case l.records <- record:
+	default:
+		dropped := l.dropped.Add(1)
+		utils.Prometheus.IncAccessLogDropped()
+		// 큐가 가득 찬 동안 매 요청마다 경고하지 않도록 일부만 기록
+		if dropped == 1 || dropped%accessLogDropWarnInterval == 0 {
+			utils.Warnf("[%s] 접근 로그 큐 가득 참, 로그 유실 (누적 %d건)", record.RequestId, dropped)
+		}
+	}
+}
+
+// Dropped 큐가 가득 차 유실된 접근 로그 수
+func (l *accessLoggerImpl) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// run 큐의 레코드를 형식에 맞게 만들어 출력 대상에 기록
+func (l *accessLoggerImpl) run() {
+	var buffer bytes.Buffer
+	for record := range l.records {
+		buffer.Reset()
+		var err error
+		if l.tmpl != nil {
+			err = l.tmpl.Execute(&buffer, record)
+		} else {
+			err = json.NewEncoder(&buffer).Encode(record)
+		}
+		if err != nil {
+			utils.Warnf("[%s] 접근 로그 생성 실패: %v", record.RequestId, err)
+			continue
+		}
+
+		if buffer.Len() == 0 || buffer.Bytes()[buffer.Len()-1] != '\n' {
+			buffer.WriteByte('\n')
+		}
+		if _, err := l.writer.Write(buffer.Bytes()); err != nil {
+			utils.Warnf("[%s] 접근 로그 기록 실패: %v", record.RequestId, err)
+		}
+	}
+}
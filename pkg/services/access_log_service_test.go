@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// TestAccessLogSampling 성공 비율 0일 때 성공한 요청만 건너뛰고 실패/폴백 요청은 항상 기록하는지 확인
+func TestAccessLogSampling(t *testing.T) {
+	tests := []struct {
+		name   string
+		record types.AccessLogRecord
+		logged bool
+	}{
+		{"success", types.AccessLogRecord{Status: 200}, false},
+		{"forced serverless success", types.AccessLogRecord{Status: 200, Forced: true}, false},
+		{"client error", types.AccessLogRecord{Status: 404}, true},
+		{"upstream error", types.AccessLogRecord{Status: 502}, true},
+		{"no server fallback", types.AccessLogRecord{Status: 200, Fallback: "no_server"}, true},
+		{"blocked", types.AccessLogRecord{Status: 200, Error: "blocked"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &accessLoggerImpl{enabled: true, sampleRate: 0, records: make(chan *types.AccessLogRecord, 1)}
+			record := tt.record
+			logger.Log(&record)
+
+			if logged := len(logger.records) == 1; logged != tt.logged {
+				t.Errorf("기록 여부: %v, 기대값: %v", logged, tt.logged)
+			}
+		})
+	}
+}
+
+func TestAccessLogDropsWhenQueueFull(t *testing.T) {
+	// 기록 고루틴 없이 작은 큐를 써서 가득 찬 상태를 만듦
+	logger := &accessLoggerImpl{enabled: true, sampleRate: 1, records: make(chan *types.AccessLogRecord, 2)}
+	for i := 0; i < 5; i++ {
+		logger.Log(&types.AccessLogRecord{Status: 200})
+	}
+
+	if dropped := logger.Dropped(); dropped != 3 {
+		t.Errorf("버린 로그 수: %d, 기대값: 3", dropped)
+	}
+}
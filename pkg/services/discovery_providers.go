@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// staticDiscoveryProvider는 SERVER_LIST 환경 변수의 고정 서버 목록을 공급합니다
+type staticDiscoveryProvider struct {
+	servers []*types.Server
+}
+
+// NewStaticDiscoveryProvider creates a provider from "URL" or "serverId=URL" entries
+func NewStaticDiscoveryProvider(entries []string) (interfaces.DiscoveryProvider, error) {
+	servers := make([]*types.Server, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		serverId, serverUrl, hasId := strings.Cut(entry, "=")
+		if !hasId {
+			serverUrl = entry
+			serverId = utils.ServerIdFromUrl(entry)
+		}
+		if serverId == "" || serverUrl == "" {
+			return nil, fmt.Errorf("SERVER_LIST 항목 형식 오류: %s", entry)
+		}
+
+		servers = append(servers, &types.Server{
+			ServerId:  strings.TrimSpace(serverId),
+			ServerUrl: strings.TrimSpace(serverUrl),
+		})
+	}
+
+	return &staticDiscoveryProvider{servers: servers}, nil
+}
+
+func (p *staticDiscoveryProvider) Name() string {
+	return "static"
+}
+
+// Run 시작 시 한 번 목록을 공급하고 ctx가 끝날 때까지 대기
+func (p *staticDiscoveryProvider) Run(ctx context.Context, updates chan<- []*types.Server) error {
+	select {
+	case updates <- p.servers:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// fileDiscoveryProvider는 JSON/YAML 서버 목록 파일을 주기적으로 확인해 변경 시 공급합니다
+type fileDiscoveryProvider struct {
+	path     string
+	interval time.Duration
+}
+
+// serverListFile은 서버 목록 파일 형식입니다 ({"servers": [...]} 또는 최상위 배열)
+type serverListFile struct {
+	Servers []types.DiscoveredServer `json:"servers" yaml:"servers"`
+}
+
+// NewFileDiscoveryProvider creates a provider watching a JSON or YAML server list file
+func NewFileDiscoveryProvider(path string, interval time.Duration) interfaces.DiscoveryProvider {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &fileDiscoveryProvider{
+		path:     path,
+		interval: interval,
+	}
+}
+
+func (p *fileDiscoveryProvider) Name() string {
+	return "file:" + p.path
+}
+
+// Run 파일 내용이 바뀔 때마다 서버 목록 공급
+func (p *fileDiscoveryProvider) Run(ctx context.Context, updates chan<- []*types.Server) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var lastHash [sha256.Size]byte
+	loaded := false
+
+	for {
+		data, err := os.ReadFile(p.path)
+		if err != nil {
+			utils.Warnf("[DISCOVERY] 서버 목록 파일 읽기 실패 (%s): %v", p.path, err)
+		} else if hash := sha256.Sum256(data); !loaded || hash != lastHash {
+			servers, err := parseServerListFile(p.path, data)
+			if err != nil {
+				// 잘못된 파일은 무시하고 마지막 정상 목록 유지
+				utils.Warnf("[DISCOVERY] 서버 목록 파일 파싱 실패 (%s): %v", p.path, err)
+			} else {
+				lastHash = hash
+				loaded = true
+				utils.Infof("[DISCOVERY] 서버 목록 파일 변경 감지: %s (서버 %d개)", p.path, len(servers))
+
+				select {
+				case updates <- servers:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseServerListFile 확장자에 따라 JSON 또는 YAML 서버 목록 파싱
+func parseServerListFile(path string, data []byte) ([]*types.Server, error) {
+	var file serverListFile
+	trimmed := bytes.TrimSpace(data)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if bytes.HasPrefix(trimmed, []byte("-")) {
+			if err := yaml.Unmarshal(data, &file.Servers); err != nil {
+				return nil, err
+			}
+		} else if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	default:
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			if err := json.Unmarshal(data, &file.Servers); err != nil {
+				return nil, err
+			}
+		} else if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	}
+
+	servers := make([]*types.Server, 0, len(file.Servers))
+	seen := make(map[string]bool, len(file.Servers))
+	for _, discovered := range file.Servers {
+		if discovered.ServerId == "" {
+			discovered.ServerId = utils.ServerIdFromUrl(discovered.ServerUrl)
+		}
+		if discovered.ServerId == "" || discovered.ServerUrl == "" {
+			return nil, fmt.Errorf("serverId 또는 serverUrl이 비어 있는 항목이 있습니다")
+		}
+		if seen[discovered.ServerId] {
+			return nil, fmt.Errorf("중복된 serverId: %s", discovered.ServerId)
+		}
+		if err := utils.ValidateLabels(discovered.Labels); err != nil {
+			return nil, err
+		}
+		seen[discovered.ServerId] = true
+		servers = append(servers, discovered.ToServer())
+	}
+
+	return servers, nil
+}
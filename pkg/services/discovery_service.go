@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// providerState는 제공자별 동기화 상태입니다
+type providerState struct {
+	owned     map[string]bool // 제공자가 관리하는 서버 ID
+	syncs     uint64
+	lastSync  *time.Time
+	lastError string
+}
+
+// discoveryServiceImpl implements the DiscoveryService interface
+type discoveryServiceImpl struct {
+	serverService interfaces.ServerService
+	providers     []interfaces.DiscoveryProvider
+
+	mutex    sync.Mutex
+	states   map[string]*providerState
+	draining map[string]bool // 디스커버리에서 빠져 드레인 중인 서버
+}
+
+// NewDiscoveryService creates a new instance of DiscoveryService
+func NewDiscoveryService(serverService interfaces.ServerService, providers ...interfaces.DiscoveryProvider) (interfaces.DiscoveryService, error) {
+	if serverService == nil {
+		return nil, errors.New("server service cannot be nil")
+	}
+
+	states := make(map[string]*providerState, len(providers))
+	for _, provider := range providers {
+		if _, exists := states[provider.Name()]; exists {
+			return nil, errors.New("중복된 디스커버리 제공자: " + provider.Name())
+		}
+		states[provider.Name()] = &providerState{owned: make(map[string]bool)}
+	}
+
+	return &discoveryServiceImpl{
+		serverService: serverService,
+		providers:     providers,
+		states:        states,
+		draining:      make(map[string]bool),
+	}, nil
+}
+
+// NewDiscoveryProviders 설정에 따라 디스커버리 제공자 목록 생성
+func NewDiscoveryProviders() ([]interfaces.DiscoveryProvider, error) {
+	cfg := configs.GetConfig().Discovery
+	providers := make([]interfaces.DiscoveryProvider, 0)
+	labels, err := utils.ParseLabels(cfg.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("DISCOVERY_LABELS 형식 오류: %w", err)
+	}
+
+	if len(cfg.ServerList) > 0 {
+		provider, err := NewStaticDiscoveryProvider(cfg.ServerList)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	if cfg.File != "" {
+		providers = append(providers, NewFileDiscoveryProvider(cfg.File, cfg.FileInterval))
+	}
+
+	if cfg.DnsName != "" {
+		provider, err := NewDnsDiscoveryProvider(types.DnsDiscoveryOptions{
+			Name:     cfg.DnsName,
+			Type:     types.DnsRecordType(strings.ToLower(cfg.DnsType)),
+			Port:     cfg.DnsPort,
+			Scheme:   cfg.DnsScheme,
+			Resolver: cfg.DnsResolver,
+			MinTtl:   cfg.DnsMinTtl,
+			MaxTtl:   cfg.DnsMaxTtl,
+			Labels:   labels,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	if cfg.K8sService != "" {
+		client, err := NewKubernetesClient(cfg.K8sKubeconfig)
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := NewKubernetesDiscoveryProvider(client, types.KubernetesDiscoveryOptions{
+			Namespace: cfg.K8sNamespace,
+			Service:   cfg.K8sService,
+			PortName:  cfg.K8sPortName,
+			Scheme:    cfg.K8sScheme,
+			PodLabels: cfg.K8sPodLabels,
+			Labels:    labels,
+		})
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// Start 모든 제공자 실행 (ctx가 끝나면 중지)
+func (d *discoveryServiceImpl) Start(ctx context.Context) {
+	for _, provider := range d.providers {
+		updates := make(chan []*types.Server, 1)
+
+		go func(provider interfaces.DiscoveryProvider) {
+			utils.Infof("[DISCOVERY] 제공자 시작: %s", provider.Name())
+			if err := provider.Run(ctx, updates); err != nil && !errors.Is(err, context.Canceled) {
+				utils.Errorf("[DISCOVERY] 제공자 종료 (%s): %v", provider.Name(), err)
+				d.recordError(provider.Name(), err)
+			}
+			close(updates)
+		}(provider)
+
+		go func(name string) {
+			for desired := range updates {
+				d.reconcile(name, desired)
+			}
+		}(provider.Name())
+	}
+}
+
+// reconcile 제공자가 알려준 서버 목록을 레지스트리에 반영
+// 새로 보이는 서버는 등록하고, 빠지거나 종료 중인 서버는 드레인 후 제거하며, 요청을 처리하지 않는 서버는 바로 제거합니다
+func (d *discoveryServiceImpl) reconcile(name string, desired []*types.Server) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	state := d.states[name]
+	owned := make(map[string]bool, len(desired))
+
+	for _, server := range desired {
+		if server.ServerId == "" {
+			continue
+		}
+
+		switch server.Endpoint {
+		case types.EndpointTerminating:
+			owned[server.ServerId] = true
+			d.drainLocked(name, server.ServerId, "종료 중")
+			continue
+		case types.EndpointStopped:
+			d.removeLocked(name, state, server.ServerId)
+			continue
+		}
+		owned[server.ServerId] = true
+
+		err := d.serverService.MergeServer(server)
+		if errors.Is(err, types.ErrServerDraining) && d.draining[server.ServerId] {
+			// 디스커버리로 드레인하던 서버가 다시 나타나면 드레인 취소
+			if cancelErr := d.serverService.CancelDrain(server.ServerId); cancelErr == nil {
+				delete(d.draining, server.ServerId)
+				utils.Infof("[DISCOVERY] 서버 재등장, 드레인 취소: %s", server.ServerId)
+				err = d.serverService.MergeServer(server)
+			}
+		}
+		if err == nil {
+			// 드레인이 끝나 제거된 뒤 다시 나타난 경우 추적 정보 정리
+			delete(d.draining, server.ServerId)
+		} else {
+			utils.Warnf("[DISCOVERY] 서버 반영 실패 (%s/%s): %v", name, server.ServerId, err)
+			state.lastError = err.Error()
+		}
+	}
+
+	for serverId := range state.owned {
+		if owned[serverId] || d.ownedByOthers(name, serverId) {
+			continue
+		}
+
+		d.drainLocked(name, serverId, "목록에서 빠짐")
+	}
+
+	now := time.Now()
+	state.owned = owned
+	state.syncs++
+	state.lastSync = &now
+	utils.Infof("[DISCOVERY] 동기화 완료: %s (서버 %d개)", name, len(owned))
+}
+
+// drainLocked 서버를 드레인 후 제거하도록 지정 (이미 드레인 중이면 무시, mutex를 잡은 상태에서 호출)
+func (d *discoveryServiceImpl) drainLocked(name string, serverId string, reason string) {
+	if d.draining[serverId] {
+		return
+	}
+
+	if _, err := d.serverService.StartDrain(serverId, 0, types.DrainActionRemove); err != nil {
+		if !errors.Is(err, types.ErrServerNotFound) {
+			utils.Warnf("[DISCOVERY] 서버 드레인 실패 (%s/%s): %v", name, serverId, err)
+		}
+		return
+	}
+	d.draining[serverId] = true
+	utils.Infof("[DISCOVERY] 서버 드레인 시작 (%s): %s (%s)", reason, serverId, name)
+}
+
+// removeLocked 요청을 처리하지 않는 서버를 드레인 없이 바로 제거 (다른 제공자가 관리하는 서버는 유지, mutex를 잡은 상태에서 호출)
+func (d *discoveryServiceImpl) removeLocked(name string, state *providerState, serverId string) {
+	if !state.owned[serverId] && !d.draining[serverId] {
+		return
+	}
+	if d.ownedByOthers(name, serverId) {
+		return
+	}
+
+	if err := d.serverService.RemoveServer(serverId); err != nil {
+		utils.Warnf("[DISCOVERY] 서버 제거 실패 (%s/%s): %v", name, serverId, err)
+		return
+	}
+	delete(d.draining, serverId)
+	utils.Infof("[DISCOVERY] 요청을 처리하지 않는 서버 제거: %s (%s)", serverId, name)
+}
+
+// ownedByOthers 다른 제공자도 같은 서버를 관리하는지 확인 (mutex를 잡은 상태에서 호출)
+func (d *discoveryServiceImpl) ownedByOthers(name string, serverId string) bool {
+	for other, state := range d.states {
+		if other != name && state.owned[serverId] {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *discoveryServiceImpl) recordError(name string, err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.states[name].lastError = err.Error()
+}
+
+// GetStatus 제공자별 동기화 현황 조회
+func (d *discoveryServiceImpl) GetStatus() []*types.DiscoveryStatus {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	statuses := make([]*types.DiscoveryStatus, 0, len(d.providers))
+	for _, provider := range d.providers {
+		state := d.states[provider.Name()]
+
+		servers := make([]string, 0, len(state.owned))
+		for serverId := range state.owned {
+			servers = append(servers, serverId)
+		}
+		sort.Strings(servers)
+
+		statuses = append(statuses, &types.DiscoveryStatus{
+			Provider:  provider.Name(),
+			Servers:   servers,
+			Syncs:     state.syncs,
+			LastSync:  state.lastSync,
+			LastError: state.lastError,
+		})
+	}
+	return statuses
+}
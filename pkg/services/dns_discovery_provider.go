@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// dnsDiscoveryProvider는 호스트 이름의 A/AAAA 또는 SRV 레코드를 주기적으로 조회해 서버 목록을 공급합니다
+type dnsDiscoveryProvider struct {
+	options   types.DnsDiscoveryOptions
+	client    *dns.Client
+	tcpClient *dns.Client // UDP 응답이 잘린 경우 재질의용
+}
+
+// NewDnsDiscoveryProvider creates a provider resolving one hostname via A/AAAA or SRV records
+func NewDnsDiscoveryProvider(options types.DnsDiscoveryOptions) (interfaces.DiscoveryProvider, error) {
+	if options.Name == "" {
+		return nil, errors.New("DNS 디스커버리 호스트 이름이 비어 있습니다")
+	}
+	if options.Type == "" {
+		options.Type = types.DnsRecordA
+	}
+	if options.Type != types.DnsRecordA && options.Type != types.DnsRecordSrv {
+		return nil, fmt.Errorf("알 수 없는 DNS 레코드 종류: %s", options.Type)
+	}
+	if options.Type == types.DnsRecordA && (options.Port <= 0 || options.Port > 65535) {
+		return nil, fmt.Errorf("잘못된 DNS 디스커버리 포트: %d", options.Port)
+	}
+	if options.Scheme == "" {
+		options.Scheme = "http"
+	}
+	if options.MinTtl <= 0 {
+		options.MinTtl = 5 * time.Second
+	}
+	if options.MaxTtl < options.MinTtl {
+		options.MaxTtl = options.MinTtl
+	}
+
+	if options.Resolver == "" {
+		resolver, err := systemResolver()
+		if err != nil {
+			return nil, err
+		}
+		options.Resolver = resolver
+	} else if _, _, err := net.SplitHostPort(options.Resolver); err != nil {
+		options.Resolver = net.JoinHostPort(options.Resolver, "53")
+	}
+
+	options.Name = dns.Fqdn(options.Name)
+
+	return &dnsDiscoveryProvider{
+		options:   options,
+		client:    &dns.Client{Timeout: 5 * time.Second},
+		tcpClient: &dns.Client{Net: "tcp", Timeout: 5 * time.Second},
+	}, nil
+}
+
+// systemResolver /etc/resolv.conf의 첫 번째 네임서버 주소
+func systemResolver() (string, error) {
+	config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil {
+		return "", fmt.Errorf("시스템 DNS 설정 읽기 실패: %w", err)
+	}
+	if len(config.Servers) == 0 {
+		return "", errors.New("시스템 DNS 서버가 없습니다")
+	}
+	return net.JoinHostPort(config.Servers[0], config.Port), nil
+}
+
+func (p *dnsDiscoveryProvider) Name() string {
+	return fmt.Sprintf("dns:%s:%s", p.options.Type, strings.TrimSuffix(p.options.Name, "."))
+}
+
+// Run 레코드 TTL마다 다시 조회하고 목록이 바뀌면 공급
+// 조회가 실패하면 마지막 목록을 유지하고 최소 간격 후 재시도합니다
+func (p *dnsDiscoveryProvider) Run(ctx context.Context, updates chan<- []*types.Server) error {
+	var lastKey string
+	loaded := false
+
+	for {
+		servers, ttl, err := p.resolve(ctx)
+		wait := p.options.MinTtl
+
+		if err != nil {
+			utils.Warnf("[DISCOVERY] DNS 조회 실패 (%s): %v", p.options.Name, err)
+		} else {
+			wait = min(max(ttl, p.options.MinTtl), p.options.MaxTtl)
+
+			if key := serverListKey(servers); !loaded || key != lastKey {
+				lastKey = key
+				loaded = true
+				utils.Infof("[DISCOVERY] DNS 레코드 변경 감지: %s (서버 %d개, 다음 조회: %s)", p.options.Name, len(servers), wait)
+
+				select {
+				case updates <- servers:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// resolve 설정된 레코드 종류로 서버 목록과 가장 짧은 TTL 조회
+func (p *dnsDiscoveryProvider) resolve(ctx context.Context) ([]*types.Server, time.Duration, error) {
+	if p.options.Type == types.DnsRecordSrv {
+		return p.resolveSrv(ctx)
+	}
+
+	servers := make([]*types.Server, 0)
+	var ttl time.Duration
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		answers, err := p.exchange(ctx, qtype)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, answer := range answers {
+			var ip net.IP
+			switch record := answer.(type) {
+			case *dns.A:
+				ip = record.A
+			case *dns.AAAA:
+				ip = record.AAAA
+			default:
+				continue
+			}
+
+			ttl = minTtl(ttl, answer.Header().Ttl)
+			serverUrl := fmt.Sprintf("%s://%s", p.options.Scheme, net.JoinHostPort(ip.String(), strconv.Itoa(p.options.Port)))
+			servers = append(servers, &types.Server{
+				ServerId:  dnsServerId(ip.String(), p.options.Port),
+				ServerUrl: serverUrl,
+				Labels:    p.labels(),
+			})
+		}
+	}
+
+	return servers, ttl, nil
+}
+
+// resolveSrv SRV 레코드의 대상 호스트와 포트로 서버 목록 구성 (우선순위, 가중치는 라벨로 기록)
+func (p *dnsDiscoveryProvider) resolveSrv(ctx context.Context) ([]*types.Server, time.Duration, error) {
+	answers, err := p.exchange(ctx, dns.TypeSRV)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	servers := make([]*types.Server, 0, len(answers))
+	var ttl time.Duration
+	for _, answer := range answers {
+		record, ok := answer.(*dns.SRV)
+		if !ok {
+			continue
+		}
+
+		ttl = minTtl(ttl, record.Hdr.Ttl)
+		target := strings.TrimSuffix(record.Target, ".")
+		port := int(record.Port)
+		labels := p.labels()
+		labels["dns.priority"] = strconv.Itoa(int(record.Priority))
+		labels["dns.weight"] = strconv.Itoa(int(record.Weight))
+		servers = append(servers, &types.Server{
+			ServerId:  dnsServerId(target, port),
+			ServerUrl: fmt.Sprintf("%s://%s", p.options.Scheme, net.JoinHostPort(target, strconv.Itoa(port))),
+			Labels:    labels,
+		})
+	}
+
+	return servers, ttl, nil
+}
+
+// labels 발견한 서버에 붙일 기본 라벨 복사본
+func (p *dnsDiscoveryProvider) labels() map[string]string {
+	labels := make(map[string]string, len(p.options.Labels)+2)
+	for key, value := range p.options.Labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// exchange DNS 질의 실행 (NXDOMAIN은 레코드 없음으로 처리)
+// UDP 응답이 잘리면(TC) 레코드 일부만 반영되지 않도록 TCP로 다시 질의합니다
+func (p *dnsDiscoveryProvider) exchange(ctx context.Context, qtype uint16) ([]dns.RR, error) {
+	message := new(dns.Msg)
+	message.SetQuestion(p.options.Name, qtype)
+
+	response, _, err := p.client.ExchangeContext(ctx, message, p.options.Resolver)
+	if err != nil {
+		return nil, err
+	}
+	if response.Truncated {
+		response, _, err = p.tcpClient.ExchangeContext(ctx, message, p.options.Resolver)
+		if err != nil {
+			return nil, fmt.Errorf("잘린 응답 TCP 재질의 실패: %w", err)
+		}
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess:
+		return response.Answer, nil
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("DNS 응답 오류: %s", dns.RcodeToString[response.Rcode])
+	}
+}
+
+// dnsServerId 주소와 포트로 서버 ID 생성
+// 다른 도메인의 같은 호스트 이름이 겹치지 않도록 전체 이름을 사용하며, 영문 소문자, 숫자, '.', '-' 외의 문자(IPv6의 ':' 등)는 '-'로 변환합니다
+func dnsServerId(host string, port int) string {
+	host = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, strings.TrimSuffix(host, "."))
+	return fmt.Sprintf("ndns-%s-%d", host, port)
+}
+
+// minTtl 0이 아닌 가장 짧은 TTL 반환 (TTL 0 레코드는 건너뛰며, 모두 0이면 0을 반환해 최소 간격으로 재조회)
+func minTtl(current time.Duration, ttl uint32) time.Duration {
+	next := time.Duration(ttl) * time.Second
+	if next == 0 {
+		return current
+	}
+	if current == 0 || next < current {
+		return next
+	}
+	return current
+}
+
+// serverListKey 서버 목록 비교용 키 (순서 무관, 라벨 변경 포함)
+func serverListKey(servers []*types.Server) string {
+	entries := make([]string, 0, len(servers))
+	for _, server := range servers {
+		entries = append(entries, fmt.Sprintf("%s=%s%v", server.ServerId, server.ServerUrl, server.Labels))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
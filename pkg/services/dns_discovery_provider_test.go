@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// startTestDnsServer 같은 포트의 UDP/TCP로 응답하는 인프로세스 DNS 서버 시작
+func startTestDnsServer(t *testing.T, udpHandler dns.HandlerFunc, tcpHandler dns.HandlerFunc) string {
+	t.Helper()
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("UDP 리스너 생성 실패: %v", err)
+	}
+	listener, err := net.Listen("tcp", packetConn.LocalAddr().String())
+	if err != nil {
+		packetConn.Close()
+		t.Fatalf("TCP 리스너 생성 실패: %v", err)
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: packetConn, Handler: udpHandler},
+		{Listener: listener, Handler: tcpHandler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go server.ActivateAndServe()
+		<-started
+		t.Cleanup(func() { server.Shutdown() })
+	}
+
+	return packetConn.LocalAddr().String()
+}
+
+// srvAnswer 질의에 SRV 레코드로 응답
+func srvAnswer(request *dns.Msg, targets ...string) *dns.Msg {
+	response := new(dns.Msg)
+	response.SetReply(request)
+	for _, target := range targets {
+		response.Answer = append(response.Answer, &dns.SRV{
+			Hdr:      dns.RR_Header{Name: request.Question[0].Name, Rrtype: dns.TypeSRV, Class: dns.ClassINET, Ttl: 30},
+			Priority: 10,
+			Weight:   5,
+			Port:     8080,
+			Target:   target,
+		})
+	}
+	return response
+}
+
+func newTestDnsProvider(t *testing.T, resolver string, recordType types.DnsRecordType, port int) *dnsDiscoveryProvider {
+	t.Helper()
+
+	provider, err := NewDnsDiscoveryProvider(types.DnsDiscoveryOptions{
+		Name:     "api.example.com",
+		Type:     recordType,
+		Port:     port,
+		Resolver: resolver,
+	})
+	if err != nil {
+		t.Fatalf("DNS 제공자 생성 실패: %v", err)
+	}
+	return provider.(*dnsDiscoveryProvider)
+}
+
+func TestDnsDiscoveryProviderResolveA(t *testing.T) {
+	handler := func(w dns.ResponseWriter, request *dns.Msg) {
+		response := new(dns.Msg)
+		response.SetReply(request)
+		header := dns.RR_Header{Name: request.Question[0].Name, Rrtype: request.Question[0].Qtype, Class: dns.ClassINET, Ttl: 20}
+		switch request.Question[0].Qtype {
+		case dns.TypeA:
+			response.Answer = append(response.Answer, &dns.A{Hdr: header, A: net.ParseIP("10.0.0.1")})
+		case dns.TypeAAAA:
+			response.Answer = append(response.Answer, &dns.AAAA{Hdr: header, AAAA: net.ParseIP("fd00::1")})
+		}
+		w.WriteMsg(response)
+	}
+	resolver := startTestDnsServer(t, handler, handler)
+
+	servers, ttl, err := newTestDnsProvider(t, resolver, types.DnsRecordA, 80).resolve(context.Background())
+	if err != nil {
+		t.Fatalf("조회 실패: %v", err)
+	}
+	if ttl != 20*time.Second {
+		t.Fatalf("TTL이 올바르지 않습니다: %s", ttl)
+	}
+
+	expected := map[string]string{
+		"ndns-10.0.0.1-80": "http://10.0.0.1:80",
+		"ndns-fd00--1-80":  "http://[fd00::1]:80",
+	}
+	if len(servers) != len(expected) {
+		t.Fatalf("서버 수가 올바르지 않습니다: %+v", servers)
+	}
+	for _, server := range servers {
+		if expected[server.ServerId] != server.ServerUrl {
+			t.Fatalf("예상하지 않은 서버: %s %s", server.ServerId, server.ServerUrl)
+		}
+	}
+}
+
+// TestDnsDiscoveryProviderSrvTargets 다른 도메인의 같은 호스트 이름이 서로 다른 서버 ID를 갖는지 확인
+func TestDnsDiscoveryProviderSrvTargets(t *testing.T) {
+	handler := func(w dns.ResponseWriter, request *dns.Msg) {
+		w.WriteMsg(srvAnswer(request, "api1.a.example.com.", "api1.b.example.com.", "API_2.b.example.com."))
+	}
+	resolver := startTestDnsServer(t, handler, handler)
+
+	servers, _, err := newTestDnsProvider(t, resolver, types.DnsRecordSrv, 0).resolve(context.Background())
+	if err != nil {
+		t.Fatalf("조회 실패: %v", err)
+	}
+
+	expected := []string{"ndns-api1.a.example.com-8080", "ndns-api1.b.example.com-8080", "ndns-api-2.b.example.com-8080"}
+	if len(servers) != len(expected) {
+		t.Fatalf("서버 수가 올바르지 않습니다: %+v", servers)
+	}
+	for i, server := range servers {
+		if server.ServerId != expected[i] {
+			t.Fatalf("서버 ID가 올바르지 않습니다: %s (예상: %s)", server.ServerId, expected[i])
+		}
+		if server.Labels["dns.priority"] != "10" || server.Labels["dns.weight"] != "5" {
+			t.Fatalf("우선순위, 가중치 라벨이 올바르지 않습니다: %v", server.Labels)
+		}
+	}
+}
+
+// TestDnsDiscoveryProviderTruncatedRetry 잘린 UDP 응답은 TCP로 다시 조회하는지 확인
+func TestDnsDiscoveryProviderTruncatedRetry(t *testing.T) {
+	udpHandler := func(w dns.ResponseWriter, request *dns.Msg) {
+		response := srvAnswer(request, "api1.example.com.")
+		response.Truncated = true
+		w.WriteMsg(response)
+	}
+	tcpHandler := func(w dns.ResponseWriter, request *dns.Msg) {
+		w.WriteMsg(srvAnswer(request, "api1.example.com.", "api2.example.com."))
+	}
+	resolver := startTestDnsServer(t, udpHandler, tcpHandler)
+
+	servers, _, err := newTestDnsProvider(t, resolver, types.DnsRecordSrv, 0).resolve(context.Background())
+	if err != nil {
+		t.Fatalf("조회 실패: %v", err)
+	}
+	if len(servers) != 2 {
+		t.Fatalf("TCP 재조회 결과 전체를 사용해야 합니다: %+v", servers)
+	}
+}
+
+func TestDnsDiscoveryProviderNameError(t *testing.T) {
+	handler := func(w dns.ResponseWriter, request *dns.Msg) {
+		response := new(dns.Msg)
+		response.SetRcode(request, dns.RcodeNameError)
+		w.WriteMsg(response)
+	}
+	resolver := startTestDnsServer(t, handler, handler)
+
+	servers, _, err := newTestDnsProvider(t, resolver, types.DnsRecordA, 80).resolve(context.Background())
+	if err != nil || len(servers) != 0 {
+		t.Fatalf("NXDOMAIN은 빈 목록이어야 합니다: %+v (%v)", servers, err)
+	}
+}
+
+// startTestMetricsServer JSON 메트릭을 응답하는 업스트림 서버 시작
+func startTestMetricsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"cpu_usage":10,"memory_usage":20}`))
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+// assertRoutable 디스커버리 목록을 반영하고 메트릭을 수집한 뒤 서버가 라우팅 대상으로 선택되는지 확인
+func assertRoutable(t *testing.T, name string, servers []*types.Server, serverId string) {
+	t.Helper()
+
+	serverService := newTestServerService(t, nil)
+	discovery, err := NewDiscoveryService(serverService)
+	if err != nil {
+		t.Fatalf("디스커버리 서비스 생성 실패: %v", err)
+	}
+	discovery.(*discoveryServiceImpl).states[name] = &providerState{owned: make(map[string]bool)}
+	discovery.(*discoveryServiceImpl).reconcile(name, servers)
+
+	historyService, err := NewHistoryService(nil)
+	if err != nil {
+		t.Fatalf("이력 서비스 생성 실패: %v", err)
+	}
+	scraper, err := NewScrapeService(serverService, historyService)
+	if err != nil {
+		t.Fatalf("수집 서비스 생성 실패: %v", err)
+	}
+	scraper.(*scrapeServiceImpl).scrapeAll(context.Background())
+
+	group := serverService.GetServerGroup()
+	for _, server := range append(group.ExcellentServers, group.GoodServers...) {
+		if server.ServerId == serverId {
+			return
+		}
+	}
+	t.Fatalf("발견한 서버가 라우팅 대상에 없습니다: %s (상태: %s)", serverId, serverStatus(t, serverService, serverId))
+}
+
+// TestDnsDiscoveredServerIsRoutable 발견한 서버가 기본 라벨로 수집 대상이 되어 라우팅되는지 확인
+func TestDnsDiscoveredServerIsRoutable(t *testing.T) {
+	upstream := startTestMetricsServer(t)
+	_, portText, _ := net.SplitHostPort(upstream.Listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+
+	handler := func(w dns.ResponseWriter, request *dns.Msg) {
+		response := new(dns.Msg)
+		response.SetReply(request)
+		if request.Question[0].Qtype == dns.TypeA {
+			response.Answer = append(response.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: request.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 20},
+				A:   net.ParseIP("127.0.0.1"),
+			})
+		}
+		w.WriteMsg(response)
+	}
+	resolver := startTestDnsServer(t, handler, handler)
+
+	labels, err := utils.ParseLabels(configs.GetConfig().Discovery.Labels)
+	if err != nil {
+		t.Fatalf("기본 디스커버리 라벨 파싱 실패: %v", err)
+	}
+	provider, err := NewDnsDiscoveryProvider(types.DnsDiscoveryOptions{
+		Name:     "api.example.com",
+		Port:     port,
+		Resolver: resolver,
+		Labels:   labels,
+	})
+	if err != nil {
+		t.Fatalf("DNS 제공자 생성 실패: %v", err)
+	}
+	servers, _, err := provider.(*dnsDiscoveryProvider).resolve(context.Background())
+	if err != nil {
+		t.Fatalf("조회 실패: %v", err)
+	}
+
+	assertRoutable(t, provider.Name(), servers, dnsServerId("127.0.0.1", port))
+}
+
+func TestMinTtlSkipsZero(t *testing.T) {
+	tests := []struct {
+		name string
+		ttls []uint32
+		want time.Duration
+	}{
+		{"shortest", []uint32{30, 10, 20}, 10 * time.Second},
+		{"zero first", []uint32{0, 30, 20}, 20 * time.Second},
+		{"zero last", []uint32{30, 0}, 30 * time.Second},
+		{"all zero", []uint32{0, 0}, 0},
+		{"empty", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ttl time.Duration
+			for _, next := range tt.ttls {
+				ttl = minTtl(ttl, next)
+			}
+			if ttl != tt.want {
+				t.Errorf("TTL: %s, 기대값: %s", ttl, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// eventSubscriber는 이벤트 버스 구독자입니다
+type eventSubscriber struct {
+	filter  types.EventFilter
+	channel chan types.RegistryEvent
+	dropped atomic.Uint64
+}
+
+// eventBusImpl implements the EventBus interface
+type eventBusImpl struct {
+	mutex       sync.RWMutex
+	subscribers map[uint64]*eventSubscriber
+	nextId      uint64
+}
+
+// NewEventBus creates a new instance of EventBus
+func NewEventBus() interfaces.EventBus {
+	return &eventBusImpl{
+		subscribers: make(map[uint64]*eventSubscriber),
+	}
+}
+
+// Publish 조건이 맞는 구독자에게 이벤트 전달 (버퍼가 가득 찬 구독자는 건너뜀)
+func (b *eventBusImpl) Publish(event types.RegistryEvent) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for id, subscriber := range b.subscribers {
+		if !subscriber.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case subscriber.channel <- event:
+		default:
+			dropped := subscriber.dropped.Add(1)
+			utils.Prometheus.IncEventDropped()
+			utils.Warnf("[EVENT] 구독자 버퍼 가득 참, 이벤트 유실 - 구독자: %d, 이벤트: %s/%s (누적 %d건)",
+				id, event.Type, event.ServerId, dropped)
+		}
+	}
+}
+
+// Subscribe 이벤트 구독
+func (b *eventBusImpl) Subscribe(filter types.EventFilter, buffer int) (<-chan types.RegistryEvent, func()) {
+	if buffer <= 0 {
+		buffer = configs.EventBufferSize
+	}
+
+	b.mutex.Lock()
+	b.nextId++
+	id := b.nextId
+	subscriber := &eventSubscriber{
+		filter:  filter,
+		channel: make(chan types.RegistryEvent, buffer),
+	}
+	b.subscribers[id] = subscriber
+	b.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, id)
+			close(subscriber.channel)
+			b.mutex.Unlock()
+		})
+	}
+
+	return subscriber.channel, unsubscribe
+}
+
+// SubscribeFunc 이벤트마다 handler 호출 (별도 고루틴에서 순서대로 실행)
+func (b *eventBusImpl) SubscribeFunc(filter types.EventFilter, handler func(types.RegistryEvent)) func() {
+	events, unsubscribe := b.Subscribe(filter, 0)
+
+	go func() {
+		for event := range events {
+			handler(event)
+		}
+	}()
+
+	return unsubscribe
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// pendingEvents 채널에 쌓인 이벤트를 모두 꺼냄
+func pendingEvents(events <-chan types.RegistryEvent) []types.RegistryEvent {
+	received := []types.RegistryEvent{}
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return received
+			}
+			received = append(received, event)
+		default:
+			return received
+		}
+	}
+}
+
+// TestEventBusFanOut 조건이 맞는 모든 구독자에게 이벤트를 전달하는지 확인
+func TestEventBusFanOut(t *testing.T) {
+	eventBus := NewEventBus()
+
+	all, unsubscribeAll := eventBus.Subscribe(types.EventFilter{}, 10)
+	defer unsubscribeAll()
+	removed, unsubscribeRemoved := eventBus.Subscribe(types.EventFilter{Types: []types.RegistryEventType{types.EventServerRemoved}}, 10)
+	defer unsubscribeRemoved()
+	serverB, unsubscribeB := eventBus.Subscribe(types.EventFilter{ServerIds: []string{"b"}}, 10)
+	defer unsubscribeB()
+
+	eventBus.Publish(types.RegistryEvent{Type: types.EventServerAdded, ServerId: "a"})
+	eventBus.Publish(types.RegistryEvent{Type: types.EventServerRemoved, ServerId: "a"})
+	eventBus.Publish(types.RegistryEvent{Type: types.EventServerAdded, ServerId: "b"})
+
+	tests := []struct {
+		name   string
+		events <-chan types.RegistryEvent
+		want   []string // 종류/서버 ID
+	}{
+		{name: "필터 없음", events: all, want: []string{"added/a", "removed/a", "added/b"}},
+		{name: "종류 필터", events: removed, want: []string{"removed/a"}},
+		{name: "서버 ID 필터", events: serverB, want: []string{"added/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := pendingEvents(tt.events)
+			if len(received) != len(tt.want) {
+				t.Fatalf("받은 이벤트 %+v, 기대 %v", received, tt.want)
+			}
+			for i, event := range received {
+				if got := string(event.Type) + "/" + event.ServerId; got != tt.want[i] {
+					t.Fatalf("%d번째 이벤트 %s, 기대 %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestEventBusDropsWhenBufferFull 느린 구독자의 버퍼가 가득 차면 그 구독자만 이벤트를 잃는지 확인
+func TestEventBusDropsWhenBufferFull(t *testing.T) {
+	eventBus := NewEventBus()
+
+	slow, unsubscribeSlow := eventBus.Subscribe(types.EventFilter{}, 1)
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := eventBus.Subscribe(types.EventFilter{}, 10)
+	defer unsubscribeFast()
+
+	for _, serverId := range []string{"a", "b", "c"} {
+		eventBus.Publish(types.RegistryEvent{Type: types.EventServerAdded, ServerId: serverId})
+	}
+
+	if received := pendingEvents(slow); len(received) != 1 || received[0].ServerId != "a" {
+		t.Fatalf("버퍼 1인 구독자는 첫 이벤트만 받아야 합니다: %+v", received)
+	}
+	if received := pendingEvents(fast); len(received) != 3 {
+		t.Fatalf("다른 구독자는 영향을 받지 않아야 합니다: %+v", received)
+	}
+	if dropped := eventBus.(*eventBusImpl).subscribers[1].dropped.Load(); dropped != 2 {
+		t.Fatalf("유실 건수 %d, 기대 2", dropped)
+	}
+}
+
+// TestEventBusUnsubscribe 구독 해지 후 채널이 닫히고 더 이상 전달하지 않는지 확인
+func TestEventBusUnsubscribe(t *testing.T) {
+	eventBus := NewEventBus()
+
+	events, unsubscribe := eventBus.Subscribe(types.EventFilter{}, 10)
+	unsubscribe()
+	unsubscribe() // 여러 번 호출해도 안전
+
+	eventBus.Publish(types.RegistryEvent{Type: types.EventServerAdded, ServerId: "a"})
+	if _, ok := <-events; ok {
+		t.Fatalf("구독 해지 후 채널이 닫혀야 합니다")
+	}
+	if count := len(eventBus.(*eventBusImpl).subscribers); count != 0 {
+		t.Fatalf("구독자 %d명이 남아 있습니다", count)
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// StartAuditLog 레지스트리 이벤트를 감사 로그로 기록하는 구독자 등록
+func StartAuditLog(eventBus interfaces.EventBus) func() {
+	return eventBus.SubscribeFunc(types.EventFilter{}, func(event types.RegistryEvent) {
+		switch event.Type {
+		case types.EventStatusChanged:
+			status := ""
+			if event.Server != nil {
+				status = event.Server.CurrentStatus
+			}
+			utils.Infof("[AUDIT] %s %s: %s -> %s", event.Type, event.ServerId, event.PreviousStatus, status)
+		case types.EventScoreChanged:
+			score := 0.0
+			if event.Server != nil && event.Server.Metrics != nil {
+				score = event.Server.Metrics.Score
+			}
+			previous := 0.0
+			if event.PreviousScore != nil {
+				previous = *event.PreviousScore
+			}
+			utils.Infof("[AUDIT] %s %s: %.2f -> %.2f", event.Type, event.ServerId, previous, score)
+		default:
+			if event.Reason != "" {
+				utils.Infof("[AUDIT] %s %s (%s)", event.Type, event.ServerId, event.Reason)
+			} else {
+				utils.Infof("[AUDIT] %s %s", event.Type, event.ServerId)
+			}
+		}
+	})
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// historyBucket은 해상도 구간 하나의 누적 값입니다
+type historyBucket struct {
+	start         time.Time
+	samples       int
+	cpuUsage      float64
+	memoryUsage   float64
+	requestRate   float64
+	errorRate     float64
+	latency       float64
+	score         float64
+	proxyRequests int64
+	proxyErrors   int64
+	proxyLatency  time.Duration
+}
+
+// merge 다른 구간의 누적 값 합산
+func (b *historyBucket) merge(other *historyBucket) {
+	b.samples += other.samples
+	b.cpuUsage += other.cpuUsage
+	b.memoryUsage += other.memoryUsage
+	b.requestRate += other.requestRate
+	b.errorRate += other.errorRate
+	b.latency += other.latency
+	b.score += other.score
+	b.proxyRequests += other.proxyRequests
+	b.proxyErrors += other.proxyErrors
+	b.proxyLatency += other.proxyLatency
+}
+
+// point 누적 값을 평균으로 변환
+func (b *historyBucket) point() *types.MetricsPoint {
+	point := &types.MetricsPoint{
+		Time:          b.start,
+		Samples:       b.samples,
+		ProxyRequests: b.proxyRequests,
+		ProxyErrors:   b.proxyErrors,
+	}
+	if b.samples > 0 {
+		count := float64(b.samples)
+		point.CPUUsage = b.cpuUsage / count
+		point.MemoryUsage = b.memoryUsage / count
+		point.RequestRate = b.requestRate / count
+		point.ErrorRate = b.errorRate / count
+		point.Latency = b.latency / count
+		point.Score = b.score / count
+	}
+	if b.proxyRequests > 0 {
+		point.ProxyLatencyMs = float64(b.proxyLatency.Microseconds()) / 1000 / float64(b.proxyRequests)
+	}
+	return point
+}
+
+// historyRing은 보관 단계 하나의 고정 크기 링 버퍼입니다
+type historyRing struct {
+	tier    types.HistoryTier
+	buckets []historyBucket
+	head    int // 가장 최근 구간 위치
+	size    int
+}
+
+func newHistoryRing(tier types.HistoryTier) *historyRing {
+	capacity := int((tier.Retention + tier.Resolution - 1) / tier.Resolution)
+	return &historyRing{
+		tier:    tier,
+		buckets: make([]historyBucket, capacity),
+		head:    -1,
+	}
+}
+
+// current 시각이 속한 구간 (없으면 가장 오래된 구간을 덮어써 새로 생성)
+func (r *historyRing) current(now time.Time) *historyBucket {
+	start := now.Truncate(r.tier.Resolution)
+	if r.size > 0 && !start.After(r.buckets[r.head].start) {
+		return &r.buckets[r.head]
+	}
+
+	r.head = (r.head + 1) % len(r.buckets)
+	r.buckets[r.head] = historyBucket{start: start}
+	if r.size < len(r.buckets) {
+		r.size++
+	}
+	return &r.buckets[r.head]
+}
+
+// each 오래된 구간부터 순회
+func (r *historyRing) each(fn func(bucket *historyBucket)) {
+	for i := r.size - 1; i >= 0; i-- {
+		index := (r.head - i + len(r.buckets)) % len(r.buckets)
+		fn(&r.buckets[index])
+	}
+}
+
+// serverHistory는 서버 하나의 단계별 이력입니다
+type serverHistory struct {
+	mutex sync.Mutex
+	rings []*historyRing
+}
+
+// historyServiceImpl implements the HistoryService interface
+type historyServiceImpl struct {
+	tiers []types.HistoryTier
+
+	mutex     sync.RWMutex
+	histories map[string]*serverHistory
+}
+
+// NewHistoryService creates a new instance of HistoryService
+func NewHistoryService(eventBus interfaces.EventBus) (interfaces.HistoryService, error) {
+	tiers, err := parseHistoryTiers(configs.GetConfig().History.Tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &historyServiceImpl{
+		tiers:     tiers,
+		histories: make(map[string]*serverHistory),
+	}
+
+	// 제거된 서버의 이력 정리
+	if eventBus != nil {
+		eventBus.SubscribeFunc(types.EventFilter{
+			Types: []types.RegistryEventType{types.EventServerRemoved},
+		}, func(event types.RegistryEvent) {
+			service.mutex.Lock()
+			delete(service.histories, event.ServerId)
+			service.mutex.Unlock()
+		})
+	}
+
+	return service, nil
+}
+
+// parseHistoryTiers "10s:1h,1m:6h" 형식의 보관 단계 파싱 (해상도 오름차순)
+func parseHistoryTiers(raw string) ([]types.HistoryTier, error) {
+	tiers := make([]types.HistoryTier, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		resolutionRaw, retentionRaw, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("HISTORY_TIERS 형식 오류: %s (해상도:보관 기간)", part)
+		}
+		resolution, err := time.ParseDuration(strings.TrimSpace(resolutionRaw))
+		if err != nil || resolution <= 0 {
+			return nil, fmt.Errorf("HISTORY_TIERS 해상도 오류: %s", part)
+		}
+		retention, err := time.ParseDuration(strings.TrimSpace(retentionRaw))
+		if err != nil || retention < resolution {
+			return nil, fmt.Errorf("HISTORY_TIERS 보관 기간 오류: %s", part)
+		}
+
+		if len(tiers) > 0 {
+			previous := tiers[len(tiers)-1]
+			if resolution <= previous.Resolution || retention <= previous.Retention {
+				return nil, fmt.Errorf("HISTORY_TIERS는 해상도와 보관 기간이 모두 늘어나는 순서여야 합니다: %s", part)
+			}
+		}
+		tiers = append(tiers, types.HistoryTier{Resolution: resolution, Retention: retention})
+	}
+
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("HISTORY_TIERS가 비어 있습니다")
+	}
+	return tiers, nil
+}
+
+// series 서버 이력 조회 (create가 true면 없을 때 생성)
+func (h *historyServiceImpl) series(serverId string, create bool) *serverHistory {
+	h.mutex.RLock()
+	history, exists := h.histories[serverId]
+	h.mutex.RUnlock()
+	if exists || !create {
+		return history
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if history, exists = h.histories[serverId]; exists {
+		return history
+	}
+
+	history = &serverHistory{rings: make([]*historyRing, 0, len(h.tiers))}
+	for _, tier := range h.tiers {
+		history.rings = append(history.rings, newHistoryRing(tier))
+	}
+	h.histories[serverId] = history
+	return history
+}
+
+// record 모든 보관 단계의 현재 구간에 값 누적 (단계마다 해상도가 달라 자연스럽게 다운샘플링됨)
+func (h *historyServiceImpl) record(serverId string, fn func(bucket *historyBucket)) {
+	history := h.series(serverId, true)
+	now := time.Now()
+
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	for _, ring := range history.rings {
+		fn(ring.current(now))
+	}
+}
+
+// RecordMetrics 서버가 보낸 메트릭 기록
+func (h *historyServiceImpl) RecordMetrics(serverId string, metrics *types.Metrics) {
+	if metrics == nil {
+		return
+	}
+
+	h.record(serverId, func(bucket *historyBucket) {
+		bucket.samples++
+		bucket.cpuUsage += metrics.CPUUsage
+		bucket.memoryUsage += metrics.MemoryUsage
+		bucket.requestRate += metrics.RequestRate
+		bucket.errorRate += metrics.ErrorRate
+		bucket.latency += metrics.Latency
+		bucket.score += metrics.Score
+	})
+}
+
+// ObserveRequest 라우터가 관측한 프록시 요청 결과 기록
+func (h *historyServiceImpl) ObserveRequest(serverId string, latency time.Duration, failed bool) {
+	h.record(serverId, func(bucket *historyBucket) {
+		bucket.proxyRequests++
+		bucket.proxyLatency += latency
+		if failed {
+			bucket.proxyErrors++
+		}
+	})
+}
+
+// Query 조회 구간을 포함하는 가장 세밀한 보관 단계에서 step 단위로 묶어 반환
+func (h *historyServiceImpl) Query(serverId string, query types.HistoryQuery) []*types.MetricsPoint {
+	points := make([]*types.MetricsPoint, 0)
+	history := h.series(serverId, false)
+	if history == nil {
+		return points
+	}
+
+	age := time.Since(query.From)
+	tierIndex := len(h.tiers) - 1
+	for i, tier := range h.tiers {
+		if tier.Retention >= age {
+			tierIndex = i
+			break
+		}
+	}
+
+	step := query.Step
+	if step < h.tiers[tierIndex].Resolution {
+		step = h.tiers[tierIndex].Resolution
+	}
+
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	var current *historyBucket
+	history.rings[tierIndex].each(func(bucket *historyBucket) {
+		if bucket.start.Before(query.From.Truncate(h.tiers[tierIndex].Resolution)) || bucket.start.After(query.To) {
+			return
+		}
+
+		start := bucket.start.Truncate(step)
+		if current == nil || !current.start.Equal(start) {
+			if current != nil {
+				points = append(points, current.point())
+			}
+			current = &historyBucket{start: start}
+		}
+		current.merge(bucket)
+	})
+	if current != nil {
+		points = append(points, current.point())
+	}
+
+	return points
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// TestParseHistoryTiers 보관 단계 형식과 순서 검증 확인
+func TestParseHistoryTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []types.HistoryTier
+		wantErr bool
+	}{
+		{
+			name: "여러 단계",
+			raw:  "10s:1h, 1m:6h,5m:24h",
+			want: []types.HistoryTier{
+				{Resolution: 10 * time.Second, Retention: time.Hour},
+				{Resolution: time.Minute, Retention: 6 * time.Hour},
+				{Resolution: 5 * time.Minute, Retention: 24 * time.Hour},
+			},
+		},
+		{
+			name: "빈 항목은 무시",
+			raw:  "10s:1h,,",
+			want: []types.HistoryTier{{Resolution: 10 * time.Second, Retention: time.Hour}},
+		},
+		{name: "비어 있음", raw: " ", wantErr: true},
+		{name: "구분자 없음", raw: "10s", wantErr: true},
+		{name: "해상도 0", raw: "0s:1h", wantErr: true},
+		{name: "보관 기간이 해상도보다 짧음", raw: "1m:30s", wantErr: true},
+		{name: "해상도가 줄어드는 순서", raw: "1m:6h,10s:24h", wantErr: true},
+		{name: "보관 기간이 줄어드는 순서", raw: "10s:6h,1m:1h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiers, err := parseHistoryTiers(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("오류가 있어야 합니다: %+v", tiers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("파싱 실패: %v", err)
+			}
+			if len(tiers) != len(tt.want) {
+				t.Fatalf("단계 %+v, 기대 %+v", tiers, tt.want)
+			}
+			for i := range tiers {
+				if tiers[i] != tt.want[i] {
+					t.Fatalf("단계 %+v, 기대 %+v", tiers, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestHistoryRingRetention 보관 기간이 지난 구간을 덮어쓰고 같은 구간 값은 누적하는지 확인
+func TestHistoryRingRetention(t *testing.T) {
+	tier := types.HistoryTier{Resolution: 10 * time.Second, Retention: 30 * time.Second}
+	start := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name       string
+		offsets    []time.Duration // 기록 시각 (start 기준)
+		wantStarts []time.Duration // 남은 구간 시작 시각 (오래된 순)
+		wantCounts []int           // 구간별 기록 수
+	}{
+		{
+			name:       "같은 구간 기록은 누적",
+			offsets:    []time.Duration{0, 3 * time.Second, 9 * time.Second},
+			wantStarts: []time.Duration{0},
+			wantCounts: []int{3},
+		},
+		{
+			name:       "보관 기간 안의 구간은 모두 유지",
+			offsets:    []time.Duration{0, 10 * time.Second, 20 * time.Second},
+			wantStarts: []time.Duration{0, 10 * time.Second, 20 * time.Second},
+			wantCounts: []int{1, 1, 1},
+		},
+		{
+			name:       "보관 기간이 지나면 가장 오래된 구간을 덮어씀",
+			offsets:    []time.Duration{0, 10 * time.Second, 20 * time.Second, 30 * time.Second, 45 * time.Second},
+			wantStarts: []time.Duration{20 * time.Second, 30 * time.Second, 40 * time.Second},
+			wantCounts: []int{1, 1, 1},
+		},
+		{
+			name:       "이전 구간 시각 기록은 최근 구간에 누적",
+			offsets:    []time.Duration{10 * time.Second, 0},
+			wantStarts: []time.Duration{10 * time.Second},
+			wantCounts: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := newHistoryRing(tier)
+			for _, offset := range tt.offsets {
+				ring.current(start.Add(offset)).samples++
+			}
+
+			var starts []time.Duration
+			var counts []int
+			ring.each(func(bucket *historyBucket) {
+				starts = append(starts, bucket.start.Sub(start))
+				counts = append(counts, bucket.samples)
+			})
+			if len(starts) != len(tt.wantStarts) {
+				t.Fatalf("구간 %v, 기대 %v", starts, tt.wantStarts)
+			}
+			for i := range starts {
+				if starts[i] != tt.wantStarts[i] || counts[i] != tt.wantCounts[i] {
+					t.Fatalf("구간 %v (기록 %v), 기대 %v (기록 %v)", starts, counts, tt.wantStarts, tt.wantCounts)
+				}
+			}
+		})
+	}
+}
+
+// TestHistoryRemovedServer 서버 제거 이벤트를 받으면 이력을 정리하는지 확인
+func TestHistoryRemovedServer(t *testing.T) {
+	eventBus := NewEventBus()
+	historyService, err := NewHistoryService(eventBus)
+	if err != nil {
+		t.Fatalf("이력 서비스 생성 실패: %v", err)
+	}
+
+	historyService.RecordMetrics("a", &types.Metrics{CPUUsage: 10})
+	historyService.ObserveRequest("a", 20*time.Millisecond, true)
+	query := types.HistoryQuery{From: time.Now().Add(-time.Minute), To: time.Now().Add(time.Minute)}
+	// 두 기록이 구간 경계에 걸칠 수 있으므로 합계로 비교
+	var samples int
+	var proxyErrors int64
+	for _, point := range historyService.Query("a", query) {
+		samples += point.Samples
+		proxyErrors += point.ProxyErrors
+	}
+	if samples != 1 || proxyErrors != 1 {
+		t.Fatalf("기록된 이력이 다릅니다 (메트릭 %d건, 프록시 오류 %d건)", samples, proxyErrors)
+	}
+
+	eventBus.Publish(types.RegistryEvent{Type: types.EventServerRemoved, ServerId: "a"})
+	deadline := time.Now().Add(time.Second)
+	for len(historyService.Query("a", query)) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("제거된 서버의 이력이 남아 있습니다")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
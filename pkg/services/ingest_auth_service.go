@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// credentialFile은 수신 경로 인증 정보 파일 형식입니다 ({"credentials": [...]} 또는 최상위 배열)
+type credentialFile struct {
+	Credentials []*types.IngestCredential `json:"credentials" yaml:"credentials"`
+}
+
+// ingestAuthServiceImpl implements the IngestAuthService interface
+type ingestAuthServiceImpl struct {
+	keys         map[string]*types.IngestCredential   // 키 ID -> HMAC 인증 정보
+	tokens       map[[32]byte]*types.IngestCredential // 토큰 해시 -> Bearer 인증 정보
+	replayWindow time.Duration
+
+	mutex     sync.Mutex
+	seen      map[string]time.Time // 사용된 서명 -> 만료 시간
+	lastPrune time.Time
+}
+
+// NewIngestAuthService creates a new instance of IngestAuthService
+// INGEST_CREDENTIALS_FILE이 없으면 시작하지 않으며, 인증 없이 수신하려면 INGEST_AUTH_DISABLED=true를 지정해야 합니다
+func NewIngestAuthService() (interfaces.IngestAuthService, error) {
+	cfg := configs.GetConfig().IngestAuth
+
+	var credentials []*types.IngestCredential
+	switch {
+	case cfg.Disabled && cfg.CredentialsFile != "":
+		return nil, errors.New("INGEST_AUTH_DISABLED와 INGEST_CREDENTIALS_FILE은 함께 사용할 수 없습니다")
+	case cfg.Disabled:
+	case cfg.CredentialsFile == "":
+		return nil, errors.New("INGEST_CREDENTIALS_FILE이 필요합니다 (인증 없이 수신하려면 INGEST_AUTH_DISABLED=true)")
+	default:
+		data, err := os.ReadFile(cfg.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("인증 정보 파일 읽기 실패: %w", err)
+		}
+		credentials, err = parseCredentialFile(cfg.CredentialsFile, data)
+		if err != nil {
+			return nil, fmt.Errorf("인증 정보 파일 파싱 실패 (%s): %w", cfg.CredentialsFile, err)
+		}
+		if len(credentials) == 0 {
+			return nil, fmt.Errorf("인증 정보 파일에 인증 정보가 없습니다: %s", cfg.CredentialsFile)
+		}
+	}
+
+	replayWindow := cfg.ReplayWindow
+	if replayWindow <= 0 {
+		return nil, fmt.Errorf("INGEST_REPLAY_WINDOW는 0보다 커야 합니다: %s", replayWindow)
+	}
+
+	service := &ingestAuthServiceImpl{
+		keys:         make(map[string]*types.IngestCredential),
+		tokens:       make(map[[32]byte]*types.IngestCredential),
+		replayWindow: replayWindow,
+		seen:         make(map[string]time.Time),
+	}
+
+	ids := make(map[string]bool, len(credentials))
+	for _, credential := range credentials {
+		if credential == nil || credential.Id == "" {
+			return nil, errors.New("인증 정보 id가 필요합니다")
+		}
+		if ids[credential.Id] {
+			return nil, fmt.Errorf("중복된 인증 정보 id: %s", credential.Id)
+		}
+		ids[credential.Id] = true
+
+		if credential.Secret == "" && credential.Token == "" {
+			return nil, fmt.Errorf("인증 정보에 secret 또는 token이 필요합니다: %s", credential.Id)
+		}
+		if credential.Secret != "" {
+			service.keys[credential.Id] = credential
+		}
+		if credential.Token != "" {
+			hash := sha256.Sum256([]byte(credential.Token))
+			if _, exists := service.tokens[hash]; exists {
+				return nil, fmt.Errorf("중복된 토큰: %s", credential.Id)
+			}
+			service.tokens[hash] = credential
+		}
+	}
+
+	if cfg.Disabled {
+		utils.Warn("INGEST_AUTH_DISABLED=true: /internal, /metrics, POST /external/stream 요청을 인증 없이 처리합니다")
+	} else {
+		utils.Infof("수신 경로 인증 정보 로드 완료 (%d개, 재사용 방지 구간: %s)", len(credentials), replayWindow)
+	}
+	return service, nil
+}
+
+// parseCredentialFile 확장자에 따라 JSON 또는 YAML 인증 정보 파싱
+func parseCredentialFile(path string, data []byte) ([]*types.IngestCredential, error) {
+	var file credentialFile
+	trimmed := bytes.TrimSpace(data)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if bytes.HasPrefix(trimmed, []byte("-")) {
+			if err := yaml.Unmarshal(data, &file.Credentials); err != nil {
+				return nil, err
+			}
+		} else if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	default:
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			if err := json.Unmarshal(data, &file.Credentials); err != nil {
+				return nil, err
+			}
+		} else if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	}
+	return file.Credentials, nil
+}
+
+// Enabled 인증 정보가 설정되어 있는지 여부
+func (a *ingestAuthServiceImpl) Enabled() bool {
+	return len(a.keys) > 0 || len(a.tokens) > 0
+}
+
+// Authenticate 서명 헤더가 있으면 HMAC 서명, 없으면 Bearer 토큰으로 호출자 확인
+func (a *ingestAuthServiceImpl) Authenticate(request *types.IngestRequest) (*types.IngestCredential, error) {
+	if request.KeyId != "" || request.Signature != "" {
+		return a.verifySignature(request, time.Now())
+	}
+
+	token, found := strings.CutPrefix(request.Authorization, "Bearer ")
+	if !found || token == "" {
+		return nil, types.ErrMissingCredential
+	}
+
+	// 해시로 찾은 뒤 상수 시간 비교 (토큰 길이/내용에 따른 시간 차이 방지)
+	hash := sha256.Sum256([]byte(token))
+	credential, exists := a.tokens[hash]
+	if !exists || subtle.ConstantTimeCompare([]byte(credential.Token), []byte(token)) != 1 {
+		return nil, types.ErrInvalidCredential
+	}
+	return credential, nil
+}
+
+// verifySignature HMAC-SHA256 서명, 서명 시각, 재사용 여부 확인
+func (a *ingestAuthServiceImpl) verifySignature(request *types.IngestRequest, now time.Time) (*types.IngestCredential, error) {
+	if request.KeyId == "" || request.Signature == "" || request.Timestamp == "" {
+		return nil, types.ErrMissingCredential
+	}
+
+	credential, exists := a.keys[request.KeyId]
+	if !exists {
+		return nil, types.ErrInvalidCredential
+	}
+
+	unix, err := strconv.ParseInt(request.Timestamp, 10, 64)
+	if err != nil {
+		return nil, types.ErrInvalidCredential
+	}
+	signedAt := time.Unix(unix, 0)
+	if signedAt.Before(now.Add(-a.replayWindow)) || signedAt.After(now.Add(a.replayWindow)) {
+		return nil, types.ErrSignatureExpired
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(request.Signature, "sha256="))
+	if err != nil || !hmac.Equal(signature, signIngestRequest(credential.Secret, request)) {
+		return nil, types.ErrInvalidCredential
+	}
+
+	// 서명이 맞는 요청만 재사용 기록 (잘못된 서명으로 기록을 채우지 못하도록)
+	if !a.markSeen(request.KeyId+":"+hex.EncodeToString(signature), signedAt.Add(a.replayWindow), now) {
+		return nil, types.ErrSignatureReplayed
+	}
+	return credential, nil
+}
+
+// markSeen 서명 사용 기록 (이미 사용된 서명이면 false)
+func (a *ingestAuthServiceImpl) markSeen(key string, expiresAt time.Time, now time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// 재사용 방지 구간이 지난 기록 정리
+	if now.Sub(a.lastPrune) > a.replayWindow {
+		for seenKey, seenExpiresAt := range a.seen {
+			if now.After(seenExpiresAt) {
+				delete(a.seen, seenKey)
+			}
+		}
+		a.lastPrune = now
+	}
+
+	if _, exists := a.seen[key]; exists {
+		return false
+	}
+	a.seen[key] = expiresAt
+	return true
+}
+
+// signIngestRequest 서명 대상 문자열(메서드 \n 요청 경로 \n 타임스탬프 \n 본문)의 HMAC-SHA256
+func signIngestRequest(secret string, request *types.IngestRequest) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(request.Method))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(request.Uri))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(request.Timestamp))
+	mac.Write([]byte("\n"))
+	mac.Write(request.Body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// newTestIngestAuthService HMAC 키 하나를 가진 인증 서비스 생성 (설정 파일 없이)
+func newTestIngestAuthService(replayWindow time.Duration) *ingestAuthServiceImpl {
+	credential := &types.IngestCredential{Id: "agent", Secret: "secret", Token: "token"}
+	return &ingestAuthServiceImpl{
+		keys:         map[string]*types.IngestCredential{credential.Id: credential},
+		tokens:       map[[32]byte]*types.IngestCredential{sha256.Sum256([]byte(credential.Token)): credential},
+		replayWindow: replayWindow,
+		seen:         make(map[string]time.Time),
+	}
+}
+
+// signedRequest secret으로 서명한 수신 요청 생성
+func signedRequest(secret string, signedAt time.Time, body string) *types.IngestRequest {
+	request := &types.IngestRequest{
+		Method:    "POST",
+		Uri:       "/internal/metrics",
+		Body:      []byte(body),
+		KeyId:     "agent",
+		Timestamp: strconv.FormatInt(signedAt.Unix(), 10),
+	}
+	request.Signature = "sha256=" + hex.EncodeToString(signIngestRequest(secret, request))
+	return request
+}
+
+// TestVerifySignature 서명, 서명 시각, 키 ID 검증 결과 확인
+func TestVerifySignature(t *testing.T) {
+	window := 5 * time.Minute
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name    string
+		request func() *types.IngestRequest
+		wantErr error
+	}{
+		{
+			name:    "올바른 서명",
+			request: func() *types.IngestRequest { return signedRequest("secret", now, "{}") },
+		},
+		{
+			name: "sha256= 접두사 없는 서명",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.Signature = request.Signature[len("sha256="):]
+				return request
+			},
+		},
+		{
+			name:    "허용 구간 안의 과거 서명",
+			request: func() *types.IngestRequest { return signedRequest("secret", now.Add(-window), "{}") },
+		},
+		{
+			name:    "허용 구간을 지난 서명",
+			request: func() *types.IngestRequest { return signedRequest("secret", now.Add(-window-time.Second), "{}") },
+			wantErr: types.ErrSignatureExpired,
+		},
+		{
+			name:    "허용 구간보다 먼 미래 서명",
+			request: func() *types.IngestRequest { return signedRequest("secret", now.Add(window+time.Second), "{}") },
+			wantErr: types.ErrSignatureExpired,
+		},
+		{
+			name:    "다른 secret으로 서명",
+			request: func() *types.IngestRequest { return signedRequest("other", now, "{}") },
+			wantErr: types.ErrInvalidCredential,
+		},
+		{
+			name: "서명 후 본문 변경",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.Body = []byte(`{"cpu_usage":1}`)
+				return request
+			},
+			wantErr: types.ErrInvalidCredential,
+		},
+		{
+			name: "알 수 없는 키 ID",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.KeyId = "unknown"
+				return request
+			},
+			wantErr: types.ErrInvalidCredential,
+		},
+		{
+			name: "hex가 아닌 서명",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.Signature = "not-hex"
+				return request
+			},
+			wantErr: types.ErrInvalidCredential,
+		},
+		{
+			name: "숫자가 아닌 타임스탬프",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.Timestamp = "yesterday"
+				return request
+			},
+			wantErr: types.ErrInvalidCredential,
+		},
+		{
+			name: "타임스탬프 누락",
+			request: func() *types.IngestRequest {
+				request := signedRequest("secret", now, "{}")
+				request.Timestamp = ""
+				return request
+			},
+			wantErr: types.ErrMissingCredential,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestIngestAuthService(window)
+			credential, err := service.verifySignature(tt.request(), now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("오류 %v, 기대 %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (credential == nil || credential.Id != "agent") {
+				t.Fatalf("인증 정보가 반환되어야 합니다: %+v", credential)
+			}
+		})
+	}
+}
+
+// TestVerifySignatureReplay 같은 서명은 한 번만 통과하고, 잘못된 서명은 재사용 기록을 남기지 않는지 확인
+func TestVerifySignatureReplay(t *testing.T) {
+	window := 5 * time.Minute
+	now := time.Unix(1_700_000_000, 0)
+	service := newTestIngestAuthService(window)
+
+	forged := signedRequest("other", now, "{}")
+	if _, err := service.verifySignature(forged, now); !errors.Is(err, types.ErrInvalidCredential) {
+		t.Fatalf("위조 서명 오류 %v", err)
+	}
+	if len(service.seen) != 0 {
+		t.Fatalf("잘못된 서명이 기록되었습니다: %v", service.seen)
+	}
+
+	request := signedRequest("secret", now, "{}")
+	if _, err := service.verifySignature(request, now); err != nil {
+		t.Fatalf("첫 요청 인증 실패: %v", err)
+	}
+	if _, err := service.verifySignature(request, now.Add(time.Second)); !errors.Is(err, types.ErrSignatureReplayed) {
+		t.Fatalf("재사용 오류 %v, 기대 %v", err, types.ErrSignatureReplayed)
+	}
+
+	// 본문이 다르면 같은 시각이라도 다른 서명
+	if _, err := service.verifySignature(signedRequest("secret", now, `{"a":1}`), now); err != nil {
+		t.Fatalf("다른 본문 요청 인증 실패: %v", err)
+	}
+
+	// 허용 구간이 지나면 재사용 기록과 관계없이 만료로 거부
+	if _, err := service.verifySignature(request, now.Add(window+time.Second)); !errors.Is(err, types.ErrSignatureExpired) {
+		t.Fatalf("만료 오류 %v, 기대 %v", err, types.ErrSignatureExpired)
+	}
+}
+
+// TestMarkSeen 사용 기록 중복 판단과 만료 기록 정리 확인
+func TestMarkSeen(t *testing.T) {
+	window := time.Minute
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name     string
+		steps    []time.Duration // 각 기록 시각 (now 기준)
+		key      string
+		at       time.Duration
+		want     bool
+		wantSeen int // 호출 후 남은 기록 수
+	}{
+		{
+			name:     "처음 보는 서명",
+			key:      "a",
+			want:     true,
+			wantSeen: 1,
+		},
+		{
+			name:     "구간 안에서 다시 사용",
+			steps:    []time.Duration{0},
+			key:      "a",
+			at:       30 * time.Second,
+			want:     false,
+			wantSeen: 1,
+		},
+		{
+			name:     "구간 안의 다른 서명은 따로 기록",
+			steps:    []time.Duration{0},
+			key:      "b",
+			at:       window - time.Second,
+			want:     true,
+			wantSeen: 2,
+		},
+		{
+			name:     "정리 주기가 지나면 만료 기록 삭제",
+			steps:    []time.Duration{0},
+			key:      "b",
+			at:       2*window + time.Second,
+			want:     true,
+			wantSeen: 1,
+		},
+		{
+			name:     "만료 정리 후 같은 서명은 다시 기록",
+			steps:    []time.Duration{0},
+			key:      "a",
+			at:       2*window + time.Second,
+			want:     true,
+			wantSeen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestIngestAuthService(window)
+			for _, step := range tt.steps {
+				at := now.Add(step)
+				if !service.markSeen("a", at.Add(window), at) {
+					t.Fatalf("준비 단계 기록 실패")
+				}
+			}
+			at := now.Add(tt.at)
+			if got := service.markSeen(tt.key, at.Add(window), at); got != tt.want {
+				t.Fatalf("결과 %v, 기대 %v", got, tt.want)
+			}
+			if len(service.seen) != tt.wantSeen {
+				t.Fatalf("남은 기록 %d개, 기대 %d개", len(service.seen), tt.wantSeen)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// 쿠버네티스 감시 재시도 간격
+const (
+	kubernetesRetryMin = time.Second
+	kubernetesRetryMax = 30 * time.Second
+)
+
+// kubernetesDiscoveryProvider는 서비스의 EndpointSlice를 감시해 준비된 엔드포인트를 서버 목록으로 공급합니다
+type kubernetesDiscoveryProvider struct {
+	client  kubernetes.Interface
+	options types.KubernetesDiscoveryOptions
+
+	slices    map[string]*discoveryv1.EndpointSlice // EndpointSlice 이름별 최신 상태
+	podLabels map[string]map[string]string          // 파드 UID별 라벨 캐시
+}
+
+// NewKubernetesClient creates a client from a kubeconfig path, or the in-cluster config when empty
+func NewKubernetesClient(kubeconfig string) (kubernetes.Interface, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfig != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("쿠버네티스 설정 로드 실패: %w", err)
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// NewKubernetesDiscoveryProvider creates a provider watching the EndpointSlices of one service
+func NewKubernetesDiscoveryProvider(client kubernetes.Interface, options types.KubernetesDiscoveryOptions) (interfaces.DiscoveryProvider, error) {
+	if client == nil {
+		return nil, errors.New("kubernetes client cannot be nil")
+	}
+	if options.Service == "" {
+		return nil, errors.New("쿠버네티스 디스커버리 서비스 이름이 비어 있습니다")
+	}
+	if options.Namespace == "" {
+		options.Namespace = "default"
+	}
+	if options.Scheme == "" {
+		options.Scheme = "http"
+	}
+
+	return &kubernetesDiscoveryProvider{
+		client:    client,
+		options:   options,
+		slices:    make(map[string]*discoveryv1.EndpointSlice),
+		podLabels: make(map[string]map[string]string),
+	}, nil
+}
+
+func (p *kubernetesDiscoveryProvider) Name() string {
+	return fmt.Sprintf("kubernetes:%s/%s", p.options.Namespace, p.options.Service)
+}
+
+// Run EndpointSlice 목록 조회 후 변경 사항을 감시하며 공급
+// 감시가 끊기면 다시 목록을 조회합니다
+func (p *kubernetesDiscoveryProvider) Run(ctx context.Context, updates chan<- []*types.Server) error {
+	retry := kubernetesRetryMin
+
+	for {
+		err := p.listAndWatch(ctx, updates)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			// 정상 종료된 감시는 바로 다시 시작
+			retry = kubernetesRetryMin
+			continue
+		}
+
+		utils.Warnf("[DISCOVERY] EndpointSlice 감시 실패 (%s), %s 후 재시도: %v", p.Name(), retry, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, kubernetesRetryMax)
+	}
+}
+
+// listAndWatch 전체 목록을 공급한 뒤 감시 채널이 닫힐 때까지 변경 사항 공급
+func (p *kubernetesDiscoveryProvider) listAndWatch(ctx context.Context, updates chan<- []*types.Server) error {
+	slicesClient := p.client.DiscoveryV1().EndpointSlices(p.options.Namespace)
+	listOptions := metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + p.options.Service,
+	}
+
+	list, err := slicesClient.List(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+
+	p.slices = make(map[string]*discoveryv1.EndpointSlice, len(list.Items))
+	for i := range list.Items {
+		p.slices[list.Items[i].Name] = &list.Items[i]
+	}
+	if err := p.publish(ctx, updates); err != nil {
+		return err
+	}
+
+	listOptions.ResourceVersion = list.ResourceVersion
+	watcher, err := slicesClient.Watch(ctx, listOptions)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				if slice, ok := event.Object.(*discoveryv1.EndpointSlice); ok {
+					p.slices[slice.Name] = slice
+				}
+			case watch.Deleted:
+				if slice, ok := event.Object.(*discoveryv1.EndpointSlice); ok {
+					delete(p.slices, slice.Name)
+				}
+			case watch.Error:
+				return fmt.Errorf("감시 오류: %v", event.Object)
+			default:
+				continue
+			}
+
+			if err := p.publish(ctx, updates); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish 현재 EndpointSlice 상태로 서버 목록을 만들어 공급
+func (p *kubernetesDiscoveryProvider) publish(ctx context.Context, updates chan<- []*types.Server) error {
+	servers := p.buildServers(ctx)
+	serving := 0
+	for _, server := range servers {
+		if server.Endpoint == types.EndpointServing {
+			serving++
+		}
+	}
+	utils.Infof("[DISCOVERY] EndpointSlice 변경 감지: %s (준비된 서버 %d개, 전체 %d개)", p.Name(), serving, len(servers))
+
+	select {
+	case updates <- servers:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildServers 엔드포인트를 상태와 함께 서버로 변환
+// 종료 중(terminating)이지만 요청을 처리하는(serving) 엔드포인트는 드레인하고, 요청을 처리하지 않는 엔드포인트는 바로 제거합니다
+func (p *kubernetesDiscoveryProvider) buildServers(ctx context.Context) []*types.Server {
+	names := make([]string, 0, len(p.slices))
+	for name := range p.slices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	servers := make([]*types.Server, 0)
+	seen := make(map[string]int)
+	usedPods := make(map[string]bool)
+
+	for _, name := range names {
+		slice := p.slices[name]
+		port, ok := p.selectPort(slice)
+		if !ok {
+			continue
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
+
+			server := p.toServer(ctx, slice, endpoint, port, usedPods)
+			server.Endpoint = endpointState(endpoint)
+			if server.Endpoint != types.EndpointServing {
+				utils.Debugf("[DISCOVERY] 요청을 받지 않는 엔드포인트: %s (%s, %s)", p.Name(), server.ServerId, server.Endpoint)
+			}
+
+			// 여러 EndpointSlice에 같은 서버가 있으면 serving > terminating > stopped 순으로 우선
+			if index, exists := seen[server.ServerId]; exists {
+				if endpointRank(server.Endpoint) < endpointRank(servers[index].Endpoint) {
+					servers[index] = server
+				}
+				continue
+			}
+			seen[server.ServerId] = len(servers)
+			servers = append(servers, server)
+		}
+	}
+
+	// 사라진 파드의 라벨 캐시 정리
+	for uid := range p.podLabels {
+		if !usedPods[uid] {
+			delete(p.podLabels, uid)
+		}
+	}
+
+	return servers
+}
+
+// selectPort 설정된 이름의 포트 (없으면 첫 번째 포트)
+func (p *kubernetesDiscoveryProvider) selectPort(slice *discoveryv1.EndpointSlice) (int, bool) {
+	for _, port := range slice.Ports {
+		if port.Port == nil {
+			continue
+		}
+		if p.options.PortName == "" || (port.Name != nil && *port.Name == p.options.PortName) {
+			return int(*port.Port), true
+		}
+	}
+	return 0, false
+}
+
+// toServer 엔드포인트를 서버 정보로 변환 (토폴로지와 파드 정보는 라벨로 기록)
+func (p *kubernetesDiscoveryProvider) toServer(ctx context.Context, slice *discoveryv1.EndpointSlice, endpoint discoveryv1.Endpoint, port int, usedPods map[string]bool) *types.Server {
+	address := endpoint.Addresses[0]
+	server := &types.Server{
+		ServerId:  dnsServerId(address, port),
+		ServerUrl: fmt.Sprintf("%s://%s", p.options.Scheme, net.JoinHostPort(address, strconv.Itoa(port))),
+		Labels:    make(map[string]string, len(p.options.Labels)),
+	}
+	for key, value := range p.options.Labels {
+		server.Labels[key] = value
+	}
+	if endpoint.Zone != nil {
+		server.Zone = *endpoint.Zone
+	}
+
+	if p.options.PodLabels && endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+		usedPods[string(endpoint.TargetRef.UID)] = true
+		for key, value := range p.lookupPodLabels(ctx, endpoint.TargetRef) {
+			server.Labels[key] = value
+		}
+	}
+
+	// 예약 라벨은 파드 라벨보다 우선
+	server.Labels["k8s.namespace"] = slice.Namespace
+	server.Labels["k8s.service"] = p.options.Service
+	if endpoint.NodeName != nil {
+		server.Labels["k8s.node"] = *endpoint.NodeName
+	}
+	if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+		server.ServerId = "ndns-" + endpoint.TargetRef.Name
+		server.Labels["k8s.pod"] = endpoint.TargetRef.Name
+	}
+
+	// 라벨 형식에 맞지 않는 값은 제외
+	for key, value := range server.Labels {
+		if err := utils.ValidateLabels(map[string]string{key: value}); err != nil {
+			delete(server.Labels, key)
+		}
+	}
+
+	return server
+}
+
+// lookupPodLabels 파드 라벨 조회 (UID별 캐시)
+func (p *kubernetesDiscoveryProvider) lookupPodLabels(ctx context.Context, ref *corev1.ObjectReference) map[string]string {
+	uid := string(ref.UID)
+	if labels, exists := p.podLabels[uid]; exists {
+		return labels
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = p.options.Namespace
+	}
+
+	pod, err := p.client.CoreV1().Pods(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		utils.Warnf("[DISCOVERY] 파드 라벨 조회 실패 (%s/%s): %v", namespace, ref.Name, err)
+		return nil
+	}
+
+	p.podLabels[uid] = pod.Labels
+	return pod.Labels
+}
+
+// endpointState 엔드포인트 조건으로 상태 결정
+// serving 조건이 없으면(이전 버전 클러스터) ready 조건을 사용하며, 조건이 비어 있으면 요청을 처리하는 것으로 간주합니다
+func endpointState(endpoint discoveryv1.Endpoint) types.EndpointState {
+	conditions := endpoint.Conditions
+	serving := conditions.Ready == nil || *conditions.Ready
+	if conditions.Serving != nil {
+		serving = *conditions.Serving
+	}
+
+	switch {
+	case !serving:
+		return types.EndpointStopped
+	case conditions.Terminating != nil && *conditions.Terminating:
+		return types.EndpointTerminating
+	default:
+		return types.EndpointServing
+	}
+}
+
+// endpointRank 중복된 엔드포인트 중 사용할 상태의 우선순위 (작을수록 우선)
+func endpointRank(state types.EndpointState) int {
+	switch state {
+	case types.EndpointServing:
+		return 0
+	case types.EndpointTerminating:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testEndpointSlice api 서비스의 EndpointSlice 생성 (파드 이름별 준비 여부 지정)
+func testEndpointSlice(name string, pods map[string]bool) *discoveryv1.EndpointSlice {
+	portName := "http"
+	port := int32(8080)
+	zone := "zone-a"
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "api"},
+		},
+		Ports: []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+	}
+	for pod, ready := range pods {
+		terminating := !ready
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{"10.0.0.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready, Terminating: &terminating},
+			Zone:       &zone,
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "default", UID: k8stypes.UID("uid-" + pod)},
+		})
+	}
+	return slice
+}
+
+// receiveServers 제공자가 공급한 다음 서버 목록 수신
+func receiveServers(t *testing.T, updates <-chan []*types.Server) map[string]*types.Server {
+	t.Helper()
+
+	select {
+	case servers := <-updates:
+		byId := make(map[string]*types.Server, len(servers))
+		for _, server := range servers {
+			byId[server.ServerId] = server
+		}
+		return byId
+	case <-time.After(5 * time.Second):
+		t.Fatal("서버 목록 공급 대기 시간 초과")
+		return nil
+	}
+}
+
+func TestKubernetesDiscoveryProviderWatch(t *testing.T) {
+	client := fake.NewClientset(
+		testEndpointSlice("api-1", map[string]bool{"api-0": true, "api-1": false}),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "api-0",
+			Namespace: "default",
+			UID:       "uid-api-0",
+			Labels:    map[string]string{"tier": "gpu", "k8s.pod": "overridden"},
+		}},
+	)
+	// 감시 이벤트를 테스트에서 직접 전달
+	watcher := watch.NewFake()
+	client.PrependWatchReactor("endpointslices", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	provider, err := NewKubernetesDiscoveryProvider(client, types.KubernetesDiscoveryOptions{
+		Namespace: "default",
+		Service:   "api",
+		PortName:  "http",
+		PodLabels: true,
+	})
+	if err != nil {
+		t.Fatalf("쿠버네티스 제공자 생성 실패: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan []*types.Server)
+	go provider.Run(ctx, updates)
+
+	// [1] 초기 목록: 준비되지 않은 파드는 stopped로 공급, 파드 라벨은 예약 라벨보다 우선하지 않음
+	servers := receiveServers(t, updates)
+	if len(servers) != 2 || servers["ndns-api-1"] == nil || servers["ndns-api-1"].Endpoint != types.EndpointStopped {
+		t.Fatalf("준비되지 않은 엔드포인트는 stopped여야 합니다: %+v", servers)
+	}
+	server, exists := servers["ndns-api-0"]
+	if !exists {
+		t.Fatalf("파드 이름으로 서버 ID를 만들어야 합니다: %+v", servers)
+	}
+	if server.Endpoint != types.EndpointServing {
+		t.Fatalf("준비된 엔드포인트는 serving이어야 합니다: %s", server.Endpoint)
+	}
+	if server.ServerUrl != "http://10.0.0.1:8080" || server.Zone != "zone-a" {
+		t.Fatalf("서버 정보가 올바르지 않습니다: %+v", server)
+	}
+	if server.Labels["tier"] != "gpu" || server.Labels["k8s.pod"] != "api-0" || server.Labels["k8s.service"] != "api" {
+		t.Fatalf("라벨이 올바르지 않습니다: %v", server.Labels)
+	}
+
+	// [2] 새 EndpointSlice 추가
+	watcher.Add(testEndpointSlice("api-2", map[string]bool{"api-2": true}))
+	if servers := receiveServers(t, updates); len(servers) != 3 || servers["ndns-api-2"] == nil || servers["ndns-api-2"].Endpoint != types.EndpointServing {
+		t.Fatalf("추가된 엔드포인트가 반영되지 않았습니다: %+v", servers)
+	}
+
+	// [3] 파드가 종료되어 요청을 받지 않으면 stopped
+	watcher.Modify(testEndpointSlice("api-2", map[string]bool{"api-2": false}))
+	if servers := receiveServers(t, updates); len(servers) != 3 || servers["ndns-api-2"].Endpoint != types.EndpointStopped {
+		t.Fatalf("종료된 엔드포인트가 stopped로 바뀌지 않았습니다: %+v", servers)
+	}
+
+	// [4] EndpointSlice 삭제
+	watcher.Delete(testEndpointSlice("api-1", nil))
+	if servers := receiveServers(t, updates); len(servers) != 1 || servers["ndns-api-2"] == nil {
+		t.Fatalf("삭제된 EndpointSlice의 서버가 남아 있습니다: %+v", servers)
+	}
+}
+
+// testConditionSlice 파드별 엔드포인트 조건을 지정한 api 서비스의 EndpointSlice 생성
+func testConditionSlice(name string, address string, port int32, pods map[string]discoveryv1.EndpointConditions) *discoveryv1.EndpointSlice {
+	portName := "http"
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "api"},
+		},
+		Ports: []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+	}
+	for pod, conditions := range pods {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: conditions,
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "default", UID: k8stypes.UID("uid-" + pod)},
+		})
+	}
+	return slice
+}
+
+func endpointConditions(ready bool, serving bool, terminating bool) discoveryv1.EndpointConditions {
+	return discoveryv1.EndpointConditions{Ready: &ready, Serving: &serving, Terminating: &terminating}
+}
+
+// startTestKubernetesProvider 감시 이벤트를 직접 전달하는 가짜 클러스터로 제공자 실행
+func startTestKubernetesProvider(t *testing.T, labels map[string]string, slices ...*discoveryv1.EndpointSlice) (interfaces.DiscoveryProvider, *watch.FakeWatcher, <-chan []*types.Server) {
+	t.Helper()
+
+	objects := make([]runtime.Object, 0, len(slices))
+	for _, slice := range slices {
+		objects = append(objects, slice)
+	}
+	client := fake.NewClientset(objects...)
+	watcher := watch.NewFake()
+	client.PrependWatchReactor("endpointslices", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	provider, err := NewKubernetesDiscoveryProvider(client, types.KubernetesDiscoveryOptions{
+		Namespace: "default",
+		Service:   "api",
+		PortName:  "http",
+		Labels:    labels,
+	})
+	if err != nil {
+		t.Fatalf("쿠버네티스 제공자 생성 실패: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	updates := make(chan []*types.Server)
+	go provider.Run(ctx, updates)
+	return provider, watcher, updates
+}
+
+// TestKubernetesDiscoveredServerIsRoutable 준비된 엔드포인트가 기본 라벨로 수집 대상이 되어 라우팅되는지 확인
+func TestKubernetesDiscoveredServerIsRoutable(t *testing.T) {
+	upstream := startTestMetricsServer(t)
+	_, portText, _ := net.SplitHostPort(upstream.Listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+
+	labels, err := utils.ParseLabels(configs.GetConfig().Discovery.Labels)
+	if err != nil {
+		t.Fatalf("기본 디스커버리 라벨 파싱 실패: %v", err)
+	}
+	provider, _, updates := startTestKubernetesProvider(t, labels, testConditionSlice("api-1", "127.0.0.1", int32(port),
+		map[string]discoveryv1.EndpointConditions{"api-0": endpointConditions(true, true, false)}))
+
+	servers := make([]*types.Server, 0)
+	for _, server := range receiveServers(t, updates) {
+		servers = append(servers, server)
+	}
+	assertRoutable(t, provider.Name(), servers, "ndns-api-0")
+}
+
+// TestKubernetesTerminatingEndpointDrain 종료 중이지만 요청을 처리하는 엔드포인트는 serving=false가 될 때까지 드레인하고,
+// 요청을 처리하지 않는 엔드포인트는 바로 제거하는지 확인
+func TestKubernetesTerminatingEndpointDrain(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+	provider, watcher, updates := startTestKubernetesProvider(t, nil, testConditionSlice("api-1", "10.0.0.1", 8080,
+		map[string]discoveryv1.EndpointConditions{
+			"api-0": endpointConditions(true, true, false),
+			"api-1": endpointConditions(true, true, false),
+		}))
+	discovery, err := NewDiscoveryService(serverService, provider)
+	if err != nil {
+		t.Fatalf("디스커버리 서비스 생성 실패: %v", err)
+	}
+	reconcile := func() {
+		t.Helper()
+		var servers []*types.Server
+		select {
+		case servers = <-updates:
+		case <-time.After(5 * time.Second):
+			t.Fatal("서버 목록 공급 대기 시간 초과")
+		}
+		discovery.(*discoveryServiceImpl).reconcile(provider.Name(), servers)
+	}
+	draining := func(serverId string) bool {
+		status, err := serverService.GetDrainStatus(serverId)
+		return err == nil && status.Phase == types.DrainPhaseDraining
+	}
+
+	reconcile()
+	for _, serverId := range []string{"ndns-api-0", "ndns-api-1"} {
+		if server, _ := serverService.GetServer(serverId); server == nil || draining(serverId) {
+			t.Fatalf("준비된 엔드포인트가 드레인 없이 등록되어야 합니다: %s", serverId)
+		}
+	}
+
+	// [1] api-0은 종료 중이지만 요청 처리 가능 (드레인), api-1은 요청 처리 불가 (바로 제거)
+	watcher.Modify(testConditionSlice("api-1", "10.0.0.1", 8080, map[string]discoveryv1.EndpointConditions{
+		"api-0": endpointConditions(false, true, true),
+		"api-1": endpointConditions(false, false, false),
+	}))
+	reconcile()
+	if server, _ := serverService.GetServer("ndns-api-0"); server == nil || !draining("ndns-api-0") {
+		t.Fatal("종료 중이지만 요청을 처리하는 엔드포인트는 드레인 중이어야 합니다")
+	}
+	if server, _ := serverService.GetServer("ndns-api-1"); server != nil {
+		t.Fatal("요청을 처리하지 않는 엔드포인트는 바로 제거되어야 합니다")
+	}
+
+	// [2] 종료 중인 상태가 이어지는 동안 드레인 유지
+	watcher.Modify(testConditionSlice("api-1", "10.0.0.1", 8080, map[string]discoveryv1.EndpointConditions{
+		"api-0": endpointConditions(false, true, true),
+	}))
+	reconcile()
+	if server, _ := serverService.GetServer("ndns-api-0"); server == nil || !draining("ndns-api-0") {
+		t.Fatal("serving=false가 될 때까지 드레인을 유지해야 합니다")
+	}
+
+	// [3] serving=false가 되면 드레인을 기다리지 않고 제거
+	watcher.Modify(testConditionSlice("api-1", "10.0.0.1", 8080, map[string]discoveryv1.EndpointConditions{
+		"api-0": endpointConditions(false, false, true),
+	}))
+	reconcile()
+	if server, _ := serverService.GetServer("ndns-api-0"); server != nil {
+		t.Fatal("serving=false가 된 엔드포인트는 바로 제거되어야 합니다")
+	}
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain 설정 로드에 필요한 필수 환경 변수 지정
+func TestMain(m *testing.M) {
+	for key, value := range map[string]string{
+		"PORT":       "0",
+		"APP_ENV":    "test",
+		"TEST_URL":   "http://localhost",
+		"URL":        "http://localhost",
+		"JWT_SECRET": "test",
+	} {
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+		}
+	}
+	os.Exit(m.Run())
+}
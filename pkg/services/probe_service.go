@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// probeServiceImpl implements the ProbeService interface
+type probeServiceImpl struct {
+	serverService interfaces.ServerService
+	path          string
+	interval      time.Duration
+	timeout       time.Duration
+	client        *fasthttp.Client
+}
+
+// NewProbeService creates a new instance of ProbeService
+func NewProbeService(serverService interfaces.ServerService) (interfaces.ProbeService, error) {
+	if serverService == nil {
+		return nil, errors.New("server service cannot be nil")
+	}
+
+	cfg := configs.GetConfig().Status
+	path := strings.TrimSpace(cfg.ProbePath)
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if path != "" && cfg.ProbeInterval <= 0 {
+		return nil, fmt.Errorf("STATUS_PROBE_INTERVAL은 0보다 커야 합니다: %s", cfg.ProbeInterval)
+	}
+	if cfg.ProbeFailureThreshold < 1 || cfg.ProbeSuccessThreshold < 1 {
+		return nil, errors.New("STATUS_PROBE_FAILURE_THRESHOLD, STATUS_PROBE_SUCCESS_THRESHOLD는 1 이상이어야 합니다")
+	}
+
+	timeout := cfg.ProbeTimeout
+	if timeout <= 0 || timeout > cfg.ProbeInterval {
+		timeout = cfg.ProbeInterval
+	}
+
+	return &probeServiceImpl{
+		serverService: serverService,
+		path:          path,
+		interval:      cfg.ProbeInterval,
+		timeout:       timeout,
+		client: &fasthttp.Client{
+			Name:                "ndns-router-prober",
+			MaxIdleConnDuration: cfg.ProbeInterval * 2,
+		},
+	}, nil
+}
+
+// Start 주기적인 헬스 체크 시작 (ctx가 끝나면 중지)
+func (p *probeServiceImpl) Start(ctx context.Context) {
+	if p.path == "" {
+		utils.Info("[PROBE] 헬스 체크 경로가 없어 헬스 체크를 사용하지 않습니다")
+		return
+	}
+
+	utils.Infof("[PROBE] 헬스 체크 시작 (경로: %s, 주기: %s)", p.path, p.interval)
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.probeAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// probeAll 등록된 모든 서버를 동시에 헬스 체크
+func (p *probeServiceImpl) probeAll(ctx context.Context) {
+	servers, err := p.serverService.GetAllServers()
+	if err != nil {
+		utils.Errorf("[PROBE] 헬스 체크 대상 조회 실패: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		if server.ServerUrl == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(server *types.Server) {
+			defer wg.Done()
+			p.probe(ctx, server)
+		}(server)
+	}
+	wg.Wait()
+}
+
+// probe 서버 하나를 헬스 체크하고 결과를 상태 머신에 전달
+func (p *probeServiceImpl) probe(ctx context.Context, server *types.Server) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	url := strings.TrimSuffix(server.ServerUrl, "/") + p.path
+	err := p.check(url)
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+		utils.Debugf("[PROBE] 헬스 체크 실패 (%s): %v", server.ServerId, err)
+	}
+	if reportErr := p.serverService.ReportProbe(server.ServerId, err == nil, reason); reportErr != nil && !errors.Is(reportErr, types.ErrServerNotFound) {
+		utils.Warnf("[PROBE] 헬스 체크 결과 반영 실패 (%s): %v", server.ServerId, reportErr)
+	}
+}
+
+// check 헬스 체크 경로 조회 (2xx, 3xx 응답만 정상)
+func (p *probeServiceImpl) check(url string) error {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod(fasthttp.MethodGet)
+
+	if err := p.client.DoTimeout(req, resp, p.timeout); err != nil {
+		return err
+	}
+	if status := resp.StatusCode(); status < fasthttp.StatusOK || status >= fasthttp.StatusBadRequest {
+		return fmt.Errorf("응답 상태 코드 %d", status)
+	}
+	return nil
+}
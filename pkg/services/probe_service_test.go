@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/valyala/fasthttp"
+)
+
+// serverStatus 게시된 서버 상태 조회
+func serverStatus(t *testing.T, serverService interfaces.ServerService, serverId string) types.ServerStatus {
+	t.Helper()
+
+	server, _ := serverService.GetServer(serverId)
+	if server == nil {
+		t.Fatalf("서버를 찾을 수 없습니다: %s", serverId)
+	}
+	return types.ServerStatus(server.CurrentStatus)
+}
+
+// TestProbeServiceRecovery 헬스 체크 실패로 unhealthy, 성공으로 warning을 거쳐 복귀하는지 확인
+func TestProbeServiceRecovery(t *testing.T) {
+	var healthy atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	serverService := newTestServerService(t, nil)
+	server := testServer("a", 0)
+	server.ServerUrl = upstream.URL
+	if err := serverService.AddServer(server); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+
+	prober := &probeServiceImpl{
+		serverService: serverService,
+		path:          "/health",
+		interval:      time.Second,
+		timeout:       time.Second,
+		client:        &fasthttp.Client{},
+	}
+
+	// 기본 기준(3회) 미만의 실패는 상태를 바꾸지 않음
+	for i := 0; i < 2; i++ {
+		prober.probeAll(context.Background())
+	}
+	if status := serverStatus(t, serverService, "a"); status != types.StatusExcellent {
+		t.Fatalf("기준 미만 실패에서는 상태를 유지해야 합니다: %s", status)
+	}
+	prober.probeAll(context.Background())
+	if status := serverStatus(t, serverService, "a"); status != types.StatusUnhealthy {
+		t.Fatalf("연속 실패 후 unhealthy여야 합니다: %s", status)
+	}
+
+	// 메트릭이 끊겨도 unknown을 거쳐 warning을 건너뛰지 않음
+	if err := serverService.MarkStale("a", "테스트"); err != nil {
+		t.Fatalf("stale 처리 실패: %v", err)
+	}
+	if status := serverStatus(t, serverService, "a"); status != types.StatusUnhealthy {
+		t.Fatalf("unhealthy 서버는 unknown으로 바뀌지 않아야 합니다: %s", status)
+	}
+
+	// 연속 성공 시 warning으로 복귀한 뒤 점수로 재평가
+	healthy.Store(true)
+	for i := 0; i < 2; i++ {
+		prober.probeAll(context.Background())
+	}
+	if status := serverStatus(t, serverService, "a"); status != types.StatusWarning {
+		t.Fatalf("연속 성공 후 warning이어야 합니다: %s", status)
+	}
+	if _, err := serverService.UpdateMetrics(&types.Server{ServerId: "a"}, &types.MetricsReport{}); err != nil {
+		t.Fatalf("메트릭 갱신 실패: %v", err)
+	}
+	if status := serverStatus(t, serverService, "a"); status != types.StatusExcellent {
+		t.Fatalf("warning에서 점수로 재평가되어야 합니다: %s", status)
+	}
+}
+
+// TestReportProxyResultThreshold 연속 프록시 실패가 기준을 넘으면 요청 경로 밖에서 unhealthy로 전환되는지 확인
+func TestReportProxyResultThreshold(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+	if err := serverService.AddServer(testServer("a", 0)); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+
+	failure := context.DeadlineExceeded
+	serverService.ReportProxyResult("a", failure)
+	serverService.ReportProxyResult("a", failure)
+	serverService.ReportProxyResult("a", nil)
+	serverService.ReportProxyResult("a", failure)
+	serverService.ReportProxyResult("a", failure)
+	if status := serverStatus(t, serverService, "a"); status != types.StatusExcellent {
+		t.Fatalf("성공으로 연속 실패가 초기화되어야 합니다: %s", status)
+	}
+
+	serverService.ReportProxyResult("a", failure)
+	deadline := time.Now().Add(5 * time.Second)
+	for serverStatus(t, serverService, "a") != types.StatusUnhealthy {
+		if time.Now().After(deadline) {
+			t.Fatal("연속 실패 후 unhealthy로 전환되지 않았습니다")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestReportProbePromotesUnknownWithoutMetrics 메트릭을 받지 못한 unknown 서버가 연속 성공으로 good이 되는지 확인
+func TestReportProbePromotesUnknownWithoutMetrics(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+	if err := serverService.MergeServer(&types.Server{ServerId: "ndns-10.0.0.1-80", ServerUrl: "http://10.0.0.1:80"}); err != nil {
+		t.Fatalf("서버 반영 실패: %v", err)
+	}
+
+	serverService.ReportProbe("ndns-10.0.0.1-80", true, "")
+	if status := serverStatus(t, serverService, "ndns-10.0.0.1-80"); status != types.StatusUnknown {
+		t.Fatalf("기준 미만 성공에서는 unknown을 유지해야 합니다: %s", status)
+	}
+	serverService.ReportProbe("ndns-10.0.0.1-80", true, "")
+	if status := serverStatus(t, serverService, "ndns-10.0.0.1-80"); status != types.StatusGood {
+		t.Fatalf("연속 성공 후 good이어야 합니다: %s", status)
+	}
+
+	// 메트릭을 받은 뒤 stale로 unknown이 된 서버는 헬스 체크만으로 승격하지 않음
+	if _, err := serverService.UpdateMetrics(&types.Server{ServerId: "ndns-10.0.0.1-80"}, &types.MetricsReport{}); err != nil {
+		t.Fatalf("메트릭 갱신 실패: %v", err)
+	}
+	if err := serverService.MarkStale("ndns-10.0.0.1-80", "테스트"); err != nil {
+		t.Fatalf("stale 처리 실패: %v", err)
+	}
+	serverService.ReportProbe("ndns-10.0.0.1-80", true, "")
+	if status := serverStatus(t, serverService, "ndns-10.0.0.1-80"); status != types.StatusUnknown {
+		t.Fatalf("메트릭이 끊긴 서버는 unknown을 유지해야 합니다: %s", status)
+	}
+}
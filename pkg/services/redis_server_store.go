@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// serverLoad는 빠른 조회용 서버 부하 정보입니다
+type serverLoad struct {
+	CPUUsage    float64 `json:"cpuUsage"`
+	MemoryUsage float64 `json:"memoryUsage"`
+	RequestRate float64 `json:"requestRate"`
+	ErrorRate   float64 `json:"errorRate"`
+	Score       float64 `json:"score"`
+}
+
+// redisServerStore는 README에 정의된 키 구조로 Redis에 서버 정보를 저장합니다
+type redisServerStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisServerStore creates a ServerStore backed by Redis
+// 테스트에서는 인프로세스 Redis 대체 서버에 연결한 클라이언트를 넘길 수 있습니다
+func NewRedisServerStore(client redis.UniversalClient) (interfaces.ServerStore, error) {
+	if client == nil {
+		return nil, errors.New("redis client cannot be nil")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configs.StoreTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("redis 연결 실패: %w", err)
+	}
+
+	return &redisServerStore{client: client}, nil
+}
+
+func (r *redisServerStore) Save(server *types.Server) error {
+	status, err := json.Marshal(server)
+	if err != nil {
+		return fmt.Errorf("서버 정보 직렬화 실패: %w", err)
+	}
+
+	load := serverLoad{}
+	if server.Metrics != nil {
+		load = serverLoad{
+			CPUUsage:    server.Metrics.CPUUsage,
+			MemoryUsage: server.Metrics.MemoryUsage,
+			RequestRate: server.Metrics.RequestRate,
+			ErrorRate:   server.Metrics.ErrorRate,
+			Score:       server.Metrics.Score,
+		}
+	}
+	loadJson, err := json.Marshal(load)
+	if err != nil {
+		return fmt.Errorf("부하 정보 직렬화 실패: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configs.StoreTimeout)
+	defer cancel()
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, configs.RedisKeyServerList, server.ServerId)
+		pipe.HSet(ctx, configs.RedisKeyServerStatus, server.ServerId, status)
+		pipe.HSet(ctx, configs.RedisKeyServerHealth, server.ServerId, server.CurrentStatus)
+		pipe.HSet(ctx, configs.RedisKeyServerLoad, server.ServerId, loadJson)
+		return nil
+	})
+	return err
+}
+
+func (r *redisServerStore) Delete(serverId string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), configs.StoreTimeout)
+	defer cancel()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SRem(ctx, configs.RedisKeyServerList, serverId)
+		pipe.HDel(ctx, configs.RedisKeyServerStatus, serverId)
+		pipe.HDel(ctx, configs.RedisKeyServerHealth, serverId)
+		pipe.HDel(ctx, configs.RedisKeyServerLoad, serverId)
+		return nil
+	})
+	return err
+}
+
+func (r *redisServerStore) LoadAll() ([]*types.Server, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), configs.StoreTimeout)
+	defer cancel()
+
+	serverIds, err := r.client.SMembers(ctx, configs.RedisKeyServerList).Result()
+	if err != nil {
+		return nil, fmt.Errorf("서버 목록 조회 실패: %w", err)
+	}
+	if len(serverIds) == 0 {
+		return []*types.Server{}, nil
+	}
+
+	values, err := r.client.HMGet(ctx, configs.RedisKeyServerStatus, serverIds...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("서버 상태 조회 실패: %w", err)
+	}
+
+	servers := make([]*types.Server, 0, len(values))
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			utils.Warnf("Redis에 상태 정보가 없는 서버 무시: %s", serverIds[i])
+			continue
+		}
+
+		server := &types.Server{}
+		if err := json.Unmarshal([]byte(raw), server); err != nil {
+			utils.Warnf("Redis 서버 정보 파싱 실패 (%s): %v", serverIds[i], err)
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	return servers, nil
+}
+
+func (r *redisServerStore) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// newTestRedisServerStore 인프로세스 Redis 대체 서버에 연결한 저장소 생성
+func newTestRedisServerStore(t *testing.T) (interfaces.ServerStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	store, err := NewRedisServerStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	if err != nil {
+		t.Fatalf("Redis 저장소 생성 실패: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, server
+}
+
+func TestRedisServerStoreSaveLoadDelete(t *testing.T) {
+	store, server := newTestRedisServerStore(t)
+
+	saved := &types.Server{
+		ServerId:      "a",
+		ServerUrl:     "http://a",
+		CurrentStatus: string(types.StatusGood),
+		Labels:        map[string]string{"tier": "gpu"},
+		Metrics:       &types.Metrics{CPUUsage: 40, Score: 70},
+	}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("저장 실패: %v", err)
+	}
+
+	// README에 정의된 키 구조로 저장되어야 함
+	if members, _ := server.SMembers(configs.RedisKeyServerList); len(members) != 1 || members[0] != "a" {
+		t.Fatalf("서버 목록 키가 올바르지 않습니다: %v", members)
+	}
+	if health := server.HGet(configs.RedisKeyServerHealth, "a"); health != string(types.StatusGood) {
+		t.Fatalf("서버 상태 키가 올바르지 않습니다: %q", health)
+	}
+	if load := server.HGet(configs.RedisKeyServerLoad, "a"); load == "" {
+		t.Fatal("서버 부하 키가 비어 있습니다")
+	}
+
+	loaded, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("복원 실패: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].ServerUrl != "http://a" || loaded[0].Labels["tier"] != "gpu" || loaded[0].Metrics.Score != 70 {
+		t.Fatalf("복원된 서버가 저장한 서버와 다릅니다: %+v", loaded)
+	}
+
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("삭제 실패: %v", err)
+	}
+	if loaded, err := store.LoadAll(); err != nil || len(loaded) != 0 {
+		t.Fatalf("삭제 후 서버가 남아 있습니다: %+v (%v)", loaded, err)
+	}
+}
+
+// TestRedisServerStoreRestore 재시작한 서버 서비스가 저장소에서 레지스트리를 복원하는지 확인
+func TestRedisServerStoreRestore(t *testing.T) {
+	store, _ := newTestRedisServerStore(t)
+
+	first := newTestServerService(t, store)
+	if err := first.AddServer(testServer("a", 0)); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+	if err := first.AddServer(testServer("b", 0)); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+	if err := first.RemoveServer("b"); err != nil {
+		t.Fatalf("서버 제거 실패: %v", err)
+	}
+
+	restarted := newTestServerService(t, store)
+	servers, err := restarted.GetAllServers()
+	if err != nil {
+		t.Fatalf("서버 목록 조회 실패: %v", err)
+	}
+	if len(servers) != 1 || servers[0].ServerId != "a" || servers[0].CurrentStatus != string(types.StatusExcellent) {
+		t.Fatalf("복원된 레지스트리가 올바르지 않습니다: %+v", servers)
+	}
+}
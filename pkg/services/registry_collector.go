@@ -0,0 +1,96 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// 서버 상태 게이지에 노출할 상태 목록
+var collectedStatuses = []types.ServerStatus{
+	types.StatusExcellent,
+	types.StatusGood,
+	types.StatusWarning,
+	types.StatusUnhealthy,
+	types.StatusUnknown,
+}
+
+// registryCollector는 수집 시점의 레지스트리 스냅샷을 서버별 게이지로 노출합니다
+// 서버 ID 라벨 개수는 등록된 서버 수로 제한됩니다
+type registryCollector struct {
+	serverService  interfaces.ServerService
+	statusDesc     *prometheus.Desc
+	scoreDesc      *prometheus.Desc
+	activeDesc     *prometheus.Desc
+	drainingDesc   *prometheus.Desc
+	registeredDesc *prometheus.Desc
+}
+
+// NewRegistryCollector creates a Prometheus collector for server registry gauges
+func NewRegistryCollector(serverService interfaces.ServerService) prometheus.Collector {
+	return &registryCollector{
+		serverService: serverService,
+		statusDesc: prometheus.NewDesc("ndns_router_server_status",
+			"서버 현재 상태 (해당 상태이면 1)", []string{"server_id", "status"}, nil),
+		scoreDesc: prometheus.NewDesc("ndns_router_server_score",
+			"서버 점수 (0-100)", []string{"server_id"}, nil),
+		activeDesc: prometheus.NewDesc("ndns_router_server_active_requests",
+			"서버별 진행 중인 프록시 요청 수", []string{"server_id"}, nil),
+		drainingDesc: prometheus.NewDesc("ndns_router_server_draining",
+			"서버 드레인 여부 (드레인 중이거나 주차되면 1)", []string{"server_id"}, nil),
+		registeredDesc: prometheus.NewDesc("ndns_router_servers",
+			"상태별 등록 서버 수", []string{"status"}, nil),
+	}
+}
+
+func (c *registryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.statusDesc
+	ch <- c.scoreDesc
+	ch <- c.activeDesc
+	ch <- c.drainingDesc
+	ch <- c.registeredDesc
+}
+
+func (c *registryCollector) Collect(ch chan<- prometheus.Metric) {
+	servers, err := c.serverService.GetAllServers()
+	if err != nil {
+		return
+	}
+
+	draining := make(map[string]bool)
+	for _, status := range c.serverService.GetDrainStatuses() {
+		draining[status.ServerId] = true
+	}
+
+	counts := make(map[types.ServerStatus]int, len(collectedStatuses))
+	for _, server := range servers {
+		current := types.ServerStatus(server.CurrentStatus)
+		counts[current]++
+
+		for _, status := range collectedStatuses {
+			value := 0.0
+			if status == current {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.statusDesc, prometheus.GaugeValue, value, server.ServerId, string(status))
+		}
+
+		score := 0.0
+		if server.Metrics != nil {
+			score = server.Metrics.Score
+		}
+		ch <- prometheus.MustNewConstMetric(c.scoreDesc, prometheus.GaugeValue, score, server.ServerId)
+		ch <- prometheus.MustNewConstMetric(c.activeDesc, prometheus.GaugeValue,
+			float64(c.serverService.GetActiveRequests(server.ServerId)), server.ServerId)
+
+		drainingValue := 0.0
+		if draining[server.ServerId] {
+			drainingValue = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.drainingDesc, prometheus.GaugeValue, drainingValue, server.ServerId)
+	}
+
+	for _, status := range collectedStatuses {
+		ch <- prometheus.MustNewConstMetric(c.registeredDesc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// NewRoutingPolicy creates a RoutingPolicy from the routing configuration
+func NewRoutingPolicy() (*types.RoutingPolicy, error) {
+	cfg := configs.GetConfig().Routing
+
+	limit2, err := utils.ParseLabelSelectors(cfg.PriorityLimit2)
+	if err != nil {
+		return nil, fmt.Errorf("ROUTING_PRIORITY_LIMIT_2 파싱 실패: %w", err)
+	}
+	limit10, err := utils.ParseLabelSelectors(cfg.PriorityLimit10)
+	if err != nil {
+		return nil, fmt.Errorf("ROUTING_PRIORITY_LIMIT_10 파싱 실패: %w", err)
+	}
+	defaults, err := utils.ParseLabelSelectors(cfg.PriorityDefault)
+	if err != nil {
+		return nil, fmt.Errorf("ROUTING_PRIORITY_DEFAULT 파싱 실패: %w", err)
+	}
+
+	return &types.RoutingPolicy{
+		PriorityLimit2:  limit2,
+		PriorityLimit10: limit10,
+		PriorityDefault: defaults,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// scoreWeight는 지표별 가중치와 정규화 범위입니다
+type scoreWeight struct {
+	metric types.ScoreMetric
+	weight float64
+	min    float64
+	max    float64
+}
+
+// scoreServiceImpl implements the ScoreService interface
+type scoreServiceImpl struct {
+	weights      []scoreWeight
+	penalties    []types.ScorePenaltyRule
+	pushedWeight float64
+}
+
+// NewScoreService creates a new instance of ScoreService
+func NewScoreService() (interfaces.ScoreService, error) {
+	cfg := configs.GetConfig().Scoring
+
+	weights := make([]scoreWeight, 0, 4)
+	totalWeight := 0.0
+	for _, item := range []struct {
+		metric types.ScoreMetric
+		weight float64
+		bounds []float64
+	}{
+		{types.ScoreMetricCPU, cfg.WeightCPU, cfg.CPURange},
+		{types.ScoreMetricMemory, cfg.WeightMemory, cfg.MemoryRange},
+		{types.ScoreMetricErrorRate, cfg.WeightErrorRate, cfg.ErrorRateRange},
+		{types.ScoreMetricLatency, cfg.WeightLatency, cfg.LatencyRange},
+	} {
+		if item.weight < 0 {
+			return nil, fmt.Errorf("%s 가중치는 음수일 수 없습니다: %v", item.metric, item.weight)
+		}
+		if len(item.bounds) != 2 || item.bounds[1] <= item.bounds[0] {
+			return nil, fmt.Errorf("%s 정규화 범위가 올바르지 않습니다: %v", item.metric, item.bounds)
+		}
+		weights = append(weights, scoreWeight{
+			metric: item.metric,
+			weight: item.weight,
+			min:    item.bounds[0],
+			max:    item.bounds[1],
+		})
+		totalWeight += item.weight
+	}
+	// 가중치가 모두 0이면 모든 서버가 같은 점수가 되어 상태 판정이 무의미해짐
+	if totalWeight <= 0 {
+		return nil, errors.New("SCORE_WEIGHT_* 가중치 합은 0보다 커야 합니다")
+	}
+
+	penalties := make([]types.ScorePenaltyRule, 0, len(cfg.Penalties))
+	for _, raw := range cfg.Penalties {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		rule, err := parsePenaltyRule(raw)
+		if err != nil {
+			return nil, err
+		}
+		penalties = append(penalties, rule)
+	}
+
+	if cfg.PushedWeight < 0 || cfg.PushedWeight > 1 {
+		return nil, fmt.Errorf("푸시 점수 반영 비율은 0-1 사이여야 합니다: %v", cfg.PushedWeight)
+	}
+
+	return &scoreServiceImpl{
+		weights:      weights,
+		penalties:    penalties,
+		pushedWeight: cfg.PushedWeight,
+	}, nil
+}
+
+// parsePenaltyRule은 "errorRate>5:cap=50" 형식의 감점 규칙을 파싱합니다
+func parsePenaltyRule(raw string) (types.ScorePenaltyRule, error) {
+	condition, action, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	if !ok {
+		return types.ScorePenaltyRule{}, fmt.Errorf("감점 규칙 형식 오류: %s", raw)
+	}
+
+	metric, threshold, ok := strings.Cut(condition, ">")
+	if !ok {
+		return types.ScorePenaltyRule{}, fmt.Errorf("감점 조건 형식 오류: %s", raw)
+	}
+	thresholdValue, err := strconv.ParseFloat(strings.TrimSpace(threshold), 64)
+	if err != nil {
+		return types.ScorePenaltyRule{}, fmt.Errorf("감점 임계값 오류: %s", raw)
+	}
+
+	actionName, value, ok := strings.Cut(action, "=")
+	if !ok {
+		return types.ScorePenaltyRule{}, fmt.Errorf("감점 동작 형식 오류: %s", raw)
+	}
+	actionValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return types.ScorePenaltyRule{}, fmt.Errorf("감점 값 오류: %s", raw)
+	}
+
+	rule := types.ScorePenaltyRule{
+		Metric:    types.ScoreMetric(strings.TrimSpace(metric)),
+		Threshold: thresholdValue,
+		Action:    types.ScorePenaltyAction(strings.TrimSpace(actionName)),
+		Value:     actionValue,
+	}
+
+	switch rule.Metric {
+	case types.ScoreMetricCPU, types.ScoreMetricMemory, types.ScoreMetricErrorRate, types.ScoreMetricLatency:
+	default:
+		return types.ScorePenaltyRule{}, fmt.Errorf("알 수 없는 감점 지표: %s", rule.Metric)
+	}
+	switch rule.Action {
+	case types.PenaltyCap, types.PenaltyMinus:
+	default:
+		return types.ScorePenaltyRule{}, fmt.Errorf("알 수 없는 감점 동작: %s", rule.Action)
+	}
+
+	return rule, nil
+}
+
+// metricValue는 지표 이름에 해당하는 메트릭 값을 반환합니다
+func metricValue(metrics *types.Metrics, metric types.ScoreMetric) float64 {
+	switch metric {
+	case types.ScoreMetricCPU:
+		return metrics.CPUUsage
+	case types.ScoreMetricMemory:
+		return metrics.MemoryUsage
+	case types.ScoreMetricErrorRate:
+		return metrics.ErrorRate
+	case types.ScoreMetricLatency:
+		return metrics.Latency
+	}
+	return 0
+}
+
+// Calculate 메트릭으로 점수 계산
+func (s *scoreServiceImpl) Calculate(metrics *types.Metrics) *types.ScoreBreakdown {
+	if metrics == nil {
+		metrics = &types.Metrics{}
+	}
+
+	breakdown := &types.ScoreBreakdown{
+		Components:   make([]types.ScoreComponent, 0, len(s.weights)),
+		Penalties:    make([]types.AppliedPenalty, 0),
+		PushedScore:  metrics.PushedScore,
+		PushedWeight: s.pushedWeight,
+	}
+
+	// [1] 지표별 정규화 및 가중 합산
+	totalWeight := 0.0
+	for _, w := range s.weights {
+		totalWeight += w.weight
+	}
+
+	computed := 0.0
+	for _, w := range s.weights {
+		value := metricValue(metrics, w.metric)
+		normalized := 1 - (value-w.min)/(w.max-w.min)
+		normalized = math.Max(0, math.Min(1, normalized))
+
+		contribution := 0.0
+		if totalWeight > 0 {
+			contribution = 100 * normalized * w.weight / totalWeight
+		}
+		computed += contribution
+
+		breakdown.Components = append(breakdown.Components, types.ScoreComponent{
+			Metric:       w.metric,
+			Value:        value,
+			Min:          w.min,
+			Max:          w.max,
+			Normalized:   normalized,
+			Weight:       w.weight,
+			Contribution: contribution,
+		})
+	}
+	breakdown.ComputedScore = computed
+
+	// [2] 푸시된 점수 반영 (선택)
+	score := computed
+	if metrics.PushedScore != nil && s.pushedWeight > 0 {
+		score = (1-s.pushedWeight)*computed + s.pushedWeight**metrics.PushedScore
+	}
+
+	// [3] 감점 규칙 적용
+	for _, rule := range s.penalties {
+		if metricValue(metrics, rule.Metric) <= rule.Threshold {
+			continue
+		}
+
+		before := score
+		switch rule.Action {
+		case types.PenaltyCap:
+			score = math.Min(score, rule.Value)
+		case types.PenaltyMinus:
+			score -= rule.Value
+		}
+		breakdown.Penalties = append(breakdown.Penalties, types.AppliedPenalty{
+			Rule:   rule,
+			Before: before,
+			After:  score,
+		})
+	}
+
+	breakdown.Score = math.Max(0, math.Min(100, score))
+	return breakdown
+}
+
+// Apply 서버 점수 갱신
+func (s *scoreServiceImpl) Apply(server *types.Server) *types.ScoreBreakdown {
+	if server.Metrics == nil {
+		server.Metrics = &types.Metrics{}
+	}
+
+	breakdown := s.Calculate(server.Metrics)
+	breakdown.ServerId = server.ServerId
+	server.Metrics.Score = breakdown.Score
+	return breakdown
+}
@@ -0,0 +1,407 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/types/dtos"
+	"github.com/sh5080/ndns-router/pkg/utils"
+	"github.com/valyala/fasthttp"
+)
+
+// scrapeAccept 수집 요청 Accept 헤더 (JSON 우선, Prometheus 텍스트 허용)
+const scrapeAccept = "application/json, text/plain;version=0.0.4;q=0.9, */*;q=0.1"
+
+// scrapeTarget은 서버별 수집 상태입니다 (mutex로 보호)
+type scrapeTarget struct {
+	url         string
+	format      types.ScrapeFormat
+	failures    int
+	stale       bool
+	lastScrape  *time.Time
+	lastSuccess *time.Time
+	lastError   string
+}
+
+// scrapeServiceImpl implements the ScrapeService interface
+type scrapeServiceImpl struct {
+	serverService  interfaces.ServerService
+	historyService interfaces.HistoryService
+	selector       *types.LabelSelector
+	interval       time.Duration
+	timeout        time.Duration
+	path           string
+	format         types.ScrapeFormat
+	threshold      int
+	names          types.ScrapeMetricNames
+	client         *fasthttp.Client
+
+	mutex   sync.Mutex
+	targets map[string]*scrapeTarget
+}
+
+// NewScrapeService creates a new instance of ScrapeService
+func NewScrapeService(serverService interfaces.ServerService, historyService interfaces.HistoryService) (interfaces.ScrapeService, error) {
+	if serverService == nil {
+		return nil, errors.New("server service cannot be nil")
+	}
+	if historyService == nil {
+		return nil, errors.New("history service cannot be nil")
+	}
+
+	cfg := configs.GetConfig().Scrape
+
+	// 셀렉터가 비어 있으면 수집 비활성화 (빈 셀렉터는 모든 서버와 일치하므로 nil로 구분)
+	var selector *types.LabelSelector
+	if strings.TrimSpace(cfg.Selector) != "" {
+		parsed, err := utils.ParseLabelSelector(cfg.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("SCRAPE_SELECTOR 파싱 실패: %w", err)
+		}
+		selector = parsed
+	}
+
+	format := types.ScrapeFormat(strings.ToLower(cfg.Format))
+	switch format {
+	case types.ScrapeFormatAuto, types.ScrapeFormatJson, types.ScrapeFormatPrometheus:
+	default:
+		return nil, fmt.Errorf("알 수 없는 SCRAPE_FORMAT: %s", cfg.Format)
+	}
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("SCRAPE_INTERVAL은 0보다 커야 합니다: %s", cfg.Interval)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 || timeout > cfg.Interval {
+		timeout = cfg.Interval
+	}
+
+	threshold := cfg.FailureThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	path := cfg.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return &scrapeServiceImpl{
+		serverService:  serverService,
+		historyService: historyService,
+		selector:       selector,
+		interval:       cfg.Interval,
+		timeout:        timeout,
+		path:           path,
+		format:         format,
+		threshold:      threshold,
+		names: types.ScrapeMetricNames{
+			CPUUsage:      cfg.MetricCPU,
+			MemoryUsage:   cfg.MetricMemory,
+			ErrorRate:     cfg.MetricErrorRate,
+			Latency:       cfg.MetricLatency,
+			TotalRequests: cfg.MetricTotalRequests,
+			ErrorRequests: cfg.MetricErrorRequests,
+		},
+		client: &fasthttp.Client{
+			Name:                "ndns-router-scraper",
+			MaxIdleConnDuration: cfg.Interval * 2,
+		},
+		targets: make(map[string]*scrapeTarget),
+	}, nil
+}
+
+// Start 주기적인 메트릭 수집 시작 (ctx가 끝나면 중지)
+func (s *scrapeServiceImpl) Start(ctx context.Context) {
+	if s.selector == nil {
+		utils.Info("[SCRAPE] 수집 대상 셀렉터가 없어 메트릭 수집을 사용하지 않습니다")
+		return
+	}
+
+	utils.Infof("[SCRAPE] 메트릭 수집 시작 (셀렉터: %s, 주기: %s)", s.selector, s.interval)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.scrapeAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// scrapeAll 셀렉터와 일치하는 모든 서버를 동시에 수집
+func (s *scrapeServiceImpl) scrapeAll(ctx context.Context) {
+	servers, err := s.serverService.GetServersBySelector(s.selector)
+	if err != nil {
+		utils.Errorf("[SCRAPE] 수집 대상 조회 실패: %v", err)
+		return
+	}
+
+	// 대상에서 빠진 서버의 수집 상태 정리
+	current := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		current[server.ServerId] = true
+	}
+	s.mutex.Lock()
+	for serverId := range s.targets {
+		if !current[serverId] {
+			delete(s.targets, serverId)
+		}
+	}
+	s.mutex.Unlock()
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		if server.ServerUrl == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(server *types.Server) {
+			defer wg.Done()
+			s.scrape(ctx, server)
+		}(server)
+	}
+	wg.Wait()
+}
+
+// scrape 서버 하나의 메트릭을 수집해 레지스트리와 이력에 반영
+func (s *scrapeServiceImpl) scrape(ctx context.Context, server *types.Server) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	url := strings.TrimSuffix(server.ServerUrl, "/") + s.path
+	report, format, err := s.fetch(url)
+	if err == nil {
+		var updated *types.Server
+		updated, err = s.serverService.UpdateMetrics(&types.Server{ServerId: server.ServerId}, report)
+		if errors.Is(err, types.ErrServerDraining) {
+			// 드레인 중인 서버는 수집 실패로 보지 않음
+			s.record(server.ServerId, url, format, nil)
+			return
+		}
+		if err == nil {
+			s.historyService.RecordMetrics(updated.ServerId, updated.Metrics)
+		}
+	}
+	utils.Prometheus.ObserveScrape(err == nil)
+
+	if failures, stale := s.record(server.ServerId, url, format, err); stale {
+		reason := fmt.Sprintf("메트릭 수집 %d회 연속 실패: %v", failures, err)
+		if markErr := s.serverService.MarkStale(server.ServerId, reason); markErr != nil && !errors.Is(markErr, types.ErrServerNotFound) {
+			utils.Warnf("[SCRAPE] 서버 상태 전환 실패 (%s): %v", server.ServerId, markErr)
+		}
+	}
+}
+
+// record 수집 결과 기록 후 연속 실패 수와 새로 unknown 처리해야 하는지 여부 반환
+func (s *scrapeServiceImpl) record(serverId string, url string, format types.ScrapeFormat, err error) (int, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	target, exists := s.targets[serverId]
+	if !exists {
+		target = &scrapeTarget{}
+		s.targets[serverId] = target
+	}
+
+	now := time.Now()
+	target.url = url
+	target.lastScrape = &now
+
+	if err == nil {
+		target.format = format
+		target.failures = 0
+		target.stale = false
+		target.lastSuccess = &now
+		target.lastError = ""
+		return 0, false
+	}
+
+	target.failures++
+	target.lastError = err.Error()
+	utils.Warnf("[SCRAPE] 메트릭 수집 실패 (%s, %d회 연속): %v", serverId, target.failures, err)
+
+	if target.stale || target.failures < s.threshold {
+		return target.failures, false
+	}
+	target.stale = true
+	return target.failures, true
+}
+
+// fetch 메트릭 경로 조회 후 응답 형식에 맞게 파싱
+func (s *scrapeServiceImpl) fetch(url string) (*types.MetricsReport, types.ScrapeFormat, error) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(url)
+	req.Header.SetMethod(fasthttp.MethodGet)
+	req.Header.Set(fasthttp.HeaderAccept, scrapeAccept)
+
+	if err := s.client.DoTimeout(req, resp, s.timeout); err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode() != fasthttp.StatusOK {
+		return nil, "", fmt.Errorf("응답 상태 코드 %d", resp.StatusCode())
+	}
+
+	format := s.format
+	if format == types.ScrapeFormatAuto {
+		format = types.ScrapeFormatPrometheus
+		if bytes.Contains(resp.Header.ContentType(), []byte("json")) {
+			format = types.ScrapeFormatJson
+		}
+	}
+
+	body := resp.Body()
+	if format == types.ScrapeFormatJson {
+		report, err := parseJsonMetrics(body)
+		return report, format, err
+	}
+	report, err := parsePrometheusMetrics(body, s.names)
+	return report, format, err
+}
+
+// parseJsonMetrics MetricsUpdateRequest 형식 JSON 파싱 (서버 정의 필드는 무시)
+func parseJsonMetrics(body []byte) (*types.MetricsReport, error) {
+	var req dtos.MetricsUpdateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("JSON 파싱 실패: %w", err)
+	}
+	return req.Report(), nil
+}
+
+// parsePrometheusMetrics Prometheus 텍스트 형식에서 설정된 이름의 메트릭 값 추출
+func parsePrometheusMetrics(body []byte, names types.ScrapeMetricNames) (*types.MetricsReport, error) {
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("Prometheus 텍스트 파싱 실패: %w", err)
+	}
+
+	report := &types.MetricsReport{}
+	found := 0
+	read := func(name string) (float64, bool) {
+		if name == "" {
+			return 0, false
+		}
+		value, ok := familyValue(families[name])
+		if ok {
+			found++
+		}
+		return value, ok
+	}
+
+	report.CPUUsage, _ = read(names.CPUUsage)
+	report.MemoryUsage, _ = read(names.MemoryUsage)
+	report.ErrorRate, _ = read(names.ErrorRate)
+	if latency, ok := read(names.Latency); ok {
+		if strings.HasSuffix(names.Latency, "_seconds") {
+			latency *= 1000
+		}
+		report.Latency = latency
+	}
+	if total, ok := read(names.TotalRequests); ok {
+		report.TotalRequests = int64(total)
+	}
+	if errorRequests, ok := read(names.ErrorRequests); ok {
+		report.ErrorRequests = int64(errorRequests)
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("설정된 메트릭이 없습니다 (%s)", strings.Join(metricNames(names), ", "))
+	}
+	return report, nil
+}
+
+// familyValue 메트릭 값 추출 (라벨이 다른 시계열은 합산, 히스토그램/서머리는 평균)
+func familyValue(family *dto.MetricFamily) (float64, bool) {
+	if family == nil || len(family.GetMetric()) == 0 {
+		return 0, false
+	}
+
+	var value, sum float64
+	var count uint64
+	for _, metric := range family.GetMetric() {
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			value += metric.GetCounter().GetValue()
+		case dto.MetricType_GAUGE:
+			value += metric.GetGauge().GetValue()
+		case dto.MetricType_HISTOGRAM:
+			sum += metric.GetHistogram().GetSampleSum()
+			count += metric.GetHistogram().GetSampleCount()
+		case dto.MetricType_SUMMARY:
+			sum += metric.GetSummary().GetSampleSum()
+			count += metric.GetSummary().GetSampleCount()
+		default:
+			value += metric.GetUntyped().GetValue()
+		}
+	}
+
+	switch family.GetType() {
+	case dto.MetricType_HISTOGRAM, dto.MetricType_SUMMARY:
+		if count == 0 {
+			return 0, true
+		}
+		return sum / float64(count), true
+	}
+	return value, true
+}
+
+// metricNames 설정된 메트릭 이름 목록 (오류 메시지용)
+func metricNames(names types.ScrapeMetricNames) []string {
+	list := make([]string, 0, 6)
+	for _, name := range []string{names.CPUUsage, names.MemoryUsage, names.ErrorRate, names.Latency, names.TotalRequests, names.ErrorRequests} {
+		if name != "" {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// GetStatus 서버별 수집 현황 조회
+func (s *scrapeServiceImpl) GetStatus() []*types.ScrapeStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	statuses := make([]*types.ScrapeStatus, 0, len(s.targets))
+	for serverId, target := range s.targets {
+		statuses = append(statuses, &types.ScrapeStatus{
+			ServerId:    serverId,
+			Url:         target.url,
+			Format:      target.format,
+			Failures:    target.failures,
+			Stale:       target.stale,
+			LastScrape:  target.lastScrape,
+			LastSuccess: target.lastSuccess,
+			LastError:   target.lastError,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ServerId < statuses[j].ServerId
+	})
+	return statuses
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// TestParsePrometheusMetrics 설정된 이름의 메트릭만 읽고 단위를 변환하는지 확인
+func TestParsePrometheusMetrics(t *testing.T) {
+	names := types.ScrapeMetricNames{
+		CPUUsage:      "cpu_usage",
+		MemoryUsage:   "memory_usage",
+		ErrorRate:     "error_rate",
+		Latency:       "request_duration_seconds",
+		TotalRequests: "requests_total",
+		ErrorRequests: "request_errors_total",
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		names   types.ScrapeMetricNames
+		want    types.MetricsReport
+		wantErr bool
+	}{
+		{
+			name: "게이지와 카운터",
+			body: "# TYPE cpu_usage gauge\ncpu_usage 42.5\n" +
+				"# TYPE memory_usage gauge\nmemory_usage 60\n" +
+				"# TYPE requests_total counter\nrequests_total 100\n" +
+				"# TYPE request_errors_total counter\nrequest_errors_total 7\n",
+			names: names,
+			want:  types.MetricsReport{CPUUsage: 42.5, MemoryUsage: 60, TotalRequests: 100, ErrorRequests: 7},
+		},
+		{
+			name: "_seconds 지연 시간은 ms로 변환",
+			body: "# TYPE request_duration_seconds histogram\n" +
+				"request_duration_seconds_bucket{le=\"+Inf\"} 4\n" +
+				"request_duration_seconds_sum 0.2\n" +
+				"request_duration_seconds_count 4\n",
+			names: names,
+			want:  types.MetricsReport{Latency: 50},
+		},
+		{
+			name:  "ms 지연 시간은 그대로",
+			body:  "# TYPE latency_ms gauge\nlatency_ms 120\n",
+			names: types.ScrapeMetricNames{Latency: "latency_ms"},
+			want:  types.MetricsReport{Latency: 120},
+		},
+		{
+			name:  "이름을 설정하지 않은 메트릭은 무시",
+			body:  "# TYPE cpu_usage gauge\ncpu_usage 10\n# TYPE error_rate gauge\nerror_rate 3\n",
+			names: types.ScrapeMetricNames{ErrorRate: "error_rate"},
+			want:  types.MetricsReport{ErrorRate: 3},
+		},
+		{
+			name:    "설정된 메트릭이 하나도 없음",
+			body:    "# TYPE other gauge\nother 1\n",
+			names:   names,
+			wantErr: true,
+		},
+		{
+			name:    "잘못된 텍스트 형식",
+			body:    "cpu_usage not-a-number\n",
+			names:   names,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := parsePrometheusMetrics([]byte(tt.body), tt.names)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("오류가 있어야 합니다: %+v", report)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("파싱 실패: %v", err)
+			}
+			if *report != tt.want {
+				t.Fatalf("결과 %+v, 기대 %+v", *report, tt.want)
+			}
+		})
+	}
+}
+
+// TestFamilyValue 메트릭 종류별로 시계열을 합산하거나 평균을 내는지 확인
+func TestFamilyValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   float64
+		wantOk bool
+	}{
+		{
+			name:   "라벨이 다른 게이지는 합산",
+			body:   "# TYPE m gauge\nm{pod=\"a\"} 1.5\nm{pod=\"b\"} 2.5\n",
+			want:   4,
+			wantOk: true,
+		},
+		{
+			name:   "라벨이 다른 카운터는 합산",
+			body:   "# TYPE m counter\nm{code=\"200\"} 10\nm{code=\"500\"} 5\n",
+			want:   15,
+			wantOk: true,
+		},
+		{
+			name:   "타입 없는 메트릭",
+			body:   "m 3\n",
+			want:   3,
+			wantOk: true,
+		},
+		{
+			name: "히스토그램은 전체 합 / 전체 개수",
+			body: "# TYPE m histogram\n" +
+				"m_bucket{pod=\"a\",le=\"+Inf\"} 2\nm_sum{pod=\"a\"} 2\nm_count{pod=\"a\"} 2\n" +
+				"m_bucket{pod=\"b\",le=\"+Inf\"} 2\nm_sum{pod=\"b\"} 6\nm_count{pod=\"b\"} 2\n",
+			want:   2,
+			wantOk: true,
+		},
+		{
+			name:   "서머리 평균",
+			body:   "# TYPE m summary\nm_sum 9\nm_count 3\n",
+			want:   3,
+			wantOk: true,
+		},
+		{
+			name:   "샘플이 없는 히스토그램은 0",
+			body:   "# TYPE m histogram\nm_bucket{le=\"+Inf\"} 0\nm_sum 0\nm_count 0\n",
+			want:   0,
+			wantOk: true,
+		},
+		{
+			name:   "메트릭 없음",
+			body:   "",
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := expfmt.NewTextParser(model.UTF8Validation)
+			families, err := parser.TextToMetricFamilies(bytes.NewReader([]byte(tt.body)))
+			if err != nil {
+				t.Fatalf("테스트 입력 파싱 실패: %v", err)
+			}
+			value, ok := familyValue(families["m"])
+			if ok != tt.wantOk || value != tt.want {
+				t.Fatalf("결과 (%v, %v), 기대 (%v, %v)", value, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// StartDrain 서버 드레인 시작 (새 요청을 받지 않고 진행 중 요청 완료 대기)
+func (s *serverServiceImpl) StartDrain(serverId string, timeout time.Duration, action types.DrainAction) (*types.DrainStatus, error) {
+	cfg := configs.GetConfig().Drain
+	if timeout <= 0 {
+		timeout = cfg.Timeout
+	}
+	if action == "" {
+		action = types.DrainAction(cfg.Action)
+	}
+	if action != types.DrainActionRemove && action != types.DrainActionPark {
+		return nil, fmt.Errorf("알 수 없는 드레인 동작: %s", action)
+	}
+
+	s.mutex.Lock()
+	if _, exists := s.servers[serverId]; !exists {
+		s.mutex.Unlock()
+		return nil, types.ErrServerNotFound
+	}
+
+	now := time.Now()
+	status, exists := s.drains[serverId]
+	if !exists || status.Phase != types.DrainPhaseDraining {
+		status = &types.DrainStatus{
+			ServerId:  serverId,
+			Phase:     types.DrainPhaseDraining,
+			StartedAt: now,
+		}
+		s.drains[serverId] = status
+	}
+	status.Action = action
+	status.Deadline = now.Add(timeout)
+
+	// 새 요청이 배정되지 않도록 서버 그룹에서 제외된 스냅샷 게시
+	s.publishLocked()
+	s.mutex.Unlock()
+
+	utils.Infof("서버 드레인 시작: %s (동작: %s, 타임아웃: %s)", serverId, action, timeout)
+
+	return s.GetDrainStatus(serverId)
+}
+
+// CancelDrain 서버 드레인 취소 (주차된 서버도 다시 라우팅 대상으로 복귀)
+func (s *serverServiceImpl) CancelDrain(serverId string) error {
+	s.mutex.Lock()
+	_, exists := s.drains[serverId]
+	if !exists {
+		s.mutex.Unlock()
+		return types.ErrNotDraining
+	}
+	delete(s.drains, serverId)
+
+	// 서버 그룹에 다시 분류된 스냅샷 게시
+	s.publishLocked()
+	s.mutex.Unlock()
+
+	utils.Infof("서버 드레인 취소: %s", serverId)
+	return nil
+}
+
+// GetDrainStatus 서버 드레인 진행 상황 조회
+func (s *serverServiceImpl) GetDrainStatus(serverId string) (*types.DrainStatus, error) {
+	s.mutex.RLock()
+	status, exists := s.drains[serverId]
+	if !exists {
+		s.mutex.RUnlock()
+		return nil, types.ErrNotDraining
+	}
+	snapshot := *status
+	s.mutex.RUnlock()
+
+	snapshot.ActiveRequests = s.GetActiveRequests(serverId)
+	return &snapshot, nil
+}
+
+// GetDrainStatuses 전체 드레인 진행 상황 조회
+func (s *serverServiceImpl) GetDrainStatuses() []*types.DrainStatus {
+	s.mutex.RLock()
+	serverIds := make([]string, 0, len(s.drains))
+	for serverId := range s.drains {
+		serverIds = append(serverIds, serverId)
+	}
+	s.mutex.RUnlock()
+	sort.Strings(serverIds)
+
+	statuses := make([]*types.DrainStatus, 0, len(serverIds))
+	for _, serverId := range serverIds {
+		if status, err := s.GetDrainStatus(serverId); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// watchDrains 주기적으로 드레인 완료 여부 점검
+func (s *serverServiceImpl) watchDrains() {
+	ticker := time.NewTicker(configs.DrainCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCollection:
+			return
+		case <-ticker.C:
+			s.checkDrains()
+		}
+	}
+}
+
+// checkDrains 유휴 상태이거나 타임아웃된 드레인 완료 처리
+func (s *serverServiceImpl) checkDrains() {
+	for _, status := range s.GetDrainStatuses() {
+		if status.Phase != types.DrainPhaseDraining {
+			continue
+		}
+
+		now := time.Now()
+		forced := now.After(status.Deadline)
+		if status.ActiveRequests > 0 && !forced {
+			continue
+		}
+
+		s.mutex.Lock()
+		// 점검 사이에 취소·재시작·제거되었을 수 있으므로 잠금 안에서 다시 확인
+		current, exists := s.drains[status.ServerId]
+		if !exists || current.Phase != types.DrainPhaseDraining {
+			s.mutex.Unlock()
+			continue
+		}
+		forced = now.After(current.Deadline)
+		if status.ActiveRequests > 0 && !forced {
+			s.mutex.Unlock()
+			continue
+		}
+
+		reason := "idle"
+		if forced {
+			reason = "timeout"
+			if status.ActiveRequests > 0 {
+				utils.Warnf("서버 드레인 타임아웃: %s (남은 요청: %d)", status.ServerId, status.ActiveRequests)
+			}
+		}
+
+		server := s.servers[status.ServerId]
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:     types.EventServerDrained,
+			ServerId: status.ServerId,
+			Time:     now,
+			Server:   server,
+			Reason:   fmt.Sprintf("%s (%s)", reason, current.Action),
+		})
+
+		if current.Action == types.DrainActionRemove {
+			s.removeServerLocked(status.ServerId, "drained")
+			s.mutex.Unlock()
+			s.persist()
+			utils.Infof("서버 드레인 완료, 제거: %s", status.ServerId)
+			continue
+		}
+
+		current.Phase = types.DrainPhaseParked
+		current.CompletedAt = &now
+		current.Forced = forced
+		s.mutex.Unlock()
+		utils.Infof("서버 드레인 완료, 주차: %s", status.ServerId)
+	}
+}
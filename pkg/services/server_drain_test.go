@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// inServerGroup 서버가 라우팅 대상(최상위, 양호) 목록에 있는지 확인
+func inServerGroup(serverService *serverServiceImpl, serverId string) bool {
+	group := serverService.GetServerGroup()
+	for _, servers := range [][]*types.Server{group.ExcellentServers, group.GoodServers} {
+		for _, server := range servers {
+			if server.ServerId == serverId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestDrainTransitions 드레인 시작부터 완료(제거, 주차)까지 상태 전이 확인
+func TestDrainTransitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      types.DrainAction
+		timeout     time.Duration
+		active      int // 점검 시점의 진행 중 요청 수
+		wantPhase   types.DrainPhase
+		wantForced  bool
+		wantRemoved bool
+	}{
+		{
+			name:      "진행 중 요청이 남아 있으면 계속 드레인",
+			action:    types.DrainActionPark,
+			timeout:   time.Hour,
+			active:    1,
+			wantPhase: types.DrainPhaseDraining,
+		},
+		{
+			name:      "유휴 상태가 되면 주차",
+			action:    types.DrainActionPark,
+			timeout:   time.Hour,
+			wantPhase: types.DrainPhaseParked,
+		},
+		{
+			name:       "타임아웃이 지나면 요청이 남아 있어도 주차",
+			action:     types.DrainActionPark,
+			timeout:    time.Nanosecond,
+			active:     1,
+			wantPhase:  types.DrainPhaseParked,
+			wantForced: true,
+		},
+		{
+			name:        "유휴 상태가 되면 제거",
+			action:      types.DrainActionRemove,
+			timeout:     time.Hour,
+			wantRemoved: true,
+		},
+		{
+			name:        "타임아웃이 지나면 요청이 남아 있어도 제거",
+			action:      types.DrainActionRemove,
+			timeout:     time.Nanosecond,
+			active:      1,
+			wantRemoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverService := newTestServerService(t, nil).(*serverServiceImpl)
+			if err := serverService.AddServer(testServer("a", 0)); err != nil {
+				t.Fatalf("서버 추가 실패: %v", err)
+			}
+			for i := 0; i < tt.active; i++ {
+				serverService.BeginRequest("a")
+			}
+
+			status, err := serverService.StartDrain("a", tt.timeout, tt.action)
+			if err != nil {
+				t.Fatalf("드레인 시작 실패: %v", err)
+			}
+			if status.Phase != types.DrainPhaseDraining || status.ActiveRequests != tt.active {
+				t.Fatalf("드레인 시작 상태가 다릅니다: %+v", status)
+			}
+			if inServerGroup(serverService, "a") {
+				t.Fatalf("드레인 중인 서버가 서버 그룹에 남아 있습니다")
+			}
+
+			time.Sleep(time.Millisecond)
+			serverService.checkDrains()
+
+			server, _ := serverService.GetServer("a")
+			if tt.wantRemoved {
+				if server != nil {
+					t.Fatalf("드레인 완료 후 서버가 제거되어야 합니다: %+v", server)
+				}
+				if _, err := serverService.GetDrainStatus("a"); !errors.Is(err, types.ErrNotDraining) {
+					t.Fatalf("제거 후 드레인 기록이 남아 있습니다: %v", err)
+				}
+				return
+			}
+
+			if server == nil {
+				t.Fatalf("서버가 제거되면 안 됩니다")
+			}
+			status, err = serverService.GetDrainStatus("a")
+			if err != nil {
+				t.Fatalf("드레인 상태 조회 실패: %v", err)
+			}
+			if status.Phase != tt.wantPhase || status.Forced != tt.wantForced {
+				t.Fatalf("단계 %s (강제 %v), 기대 %s (강제 %v)", status.Phase, status.Forced, tt.wantPhase, tt.wantForced)
+			}
+			if (status.CompletedAt != nil) != (tt.wantPhase == types.DrainPhaseParked) {
+				t.Fatalf("완료 시간이 단계와 맞지 않습니다: %+v", status)
+			}
+			if inServerGroup(serverService, "a") {
+				t.Fatalf("드레인 중이거나 주차된 서버가 서버 그룹에 있습니다")
+			}
+		})
+	}
+}
+
+// TestDrainCancelAndRestart 주차된 서버의 드레인 취소, 재시작과 잘못된 요청 처리 확인
+func TestDrainCancelAndRestart(t *testing.T) {
+	serverService := newTestServerService(t, nil).(*serverServiceImpl)
+	if err := serverService.AddServer(testServer("a", 0)); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+
+	if _, err := serverService.StartDrain("missing", time.Hour, types.DrainActionPark); !errors.Is(err, types.ErrServerNotFound) {
+		t.Fatalf("없는 서버 드레인 오류 %v", err)
+	}
+	if _, err := serverService.StartDrain("a", time.Hour, "delete"); err == nil {
+		t.Fatalf("알 수 없는 드레인 동작은 거부해야 합니다")
+	}
+	if err := serverService.CancelDrain("a"); !errors.Is(err, types.ErrNotDraining) {
+		t.Fatalf("드레인 중이 아닌 서버 취소 오류 %v", err)
+	}
+
+	// 주차
+	if _, err := serverService.StartDrain("a", time.Hour, types.DrainActionPark); err != nil {
+		t.Fatalf("드레인 시작 실패: %v", err)
+	}
+	serverService.checkDrains()
+	parked, _ := serverService.GetDrainStatus("a")
+	if parked.Phase != types.DrainPhaseParked {
+		t.Fatalf("주차 상태여야 합니다: %+v", parked)
+	}
+
+	// 드레인 중이거나 주차된 서버는 다시 추가할 수 없음
+	if err := serverService.AddServer(testServer("a", 0)); !errors.Is(err, types.ErrServerDraining) {
+		t.Fatalf("주차된 서버 추가 오류 %v, 기대 %v", err, types.ErrServerDraining)
+	}
+
+	// 주차된 서버를 다시 드레인하면 새로 시작하고 동작을 바꿀 수 있음
+	restarted, err := serverService.StartDrain("a", time.Hour, types.DrainActionRemove)
+	if err != nil {
+		t.Fatalf("드레인 재시작 실패: %v", err)
+	}
+	if restarted.Phase != types.DrainPhaseDraining || restarted.Action != types.DrainActionRemove || restarted.CompletedAt != nil {
+		t.Fatalf("드레인이 새로 시작되어야 합니다: %+v", restarted)
+	}
+
+	// 취소하면 다시 라우팅 대상으로 복귀
+	if err := serverService.CancelDrain("a"); err != nil {
+		t.Fatalf("드레인 취소 실패: %v", err)
+	}
+	if !inServerGroup(serverService, "a") {
+		t.Fatalf("드레인 취소 후 서버 그룹에 있어야 합니다")
+	}
+	serverService.checkDrains()
+	if server, _ := serverService.GetServer("a"); server == nil {
+		t.Fatalf("취소된 드레인이 서버를 제거했습니다")
+	}
+}
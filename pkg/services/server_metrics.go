@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// counterSample은 마지막으로 받은 누적 요청 카운터입니다
+type counterSample struct {
+	total  int64
+	errors int64
+	at     time.Time
+}
+
+// UpdateMetrics 푸시된 메트릭을 기존 서버 정보에 병합 (없으면 등록)
+// 요청률과 에러율은 직전 푸시와의 누적 카운터 차이로 계산하고, 병합된 메트릭으로 점수와 상태를 다시 결정합니다
+func (s *serverServiceImpl) UpdateMetrics(server *types.Server, report *types.MetricsReport) (*types.Server, error) {
+	if server == nil || server.ServerId == "" {
+		return nil, errors.New("server id cannot be empty")
+	}
+	if report == nil {
+		return nil, errors.New("metrics report cannot be nil")
+	}
+	if err := utils.ValidateLabels(server.Labels); err != nil {
+		return nil, err
+	}
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 드레인 중이거나 주차된 서버는 갱신하지 않음
+	if _, draining := s.drains[server.ServerId]; draining {
+		utils.Warnf("드레인 중인 서버 갱신 무시: %s", server.ServerId)
+		return nil, types.ErrServerDraining
+	}
+
+	// 게시된 서버 객체는 변경하지 않고 복사본에 병합
+	var stored types.Server
+	metrics := types.Metrics{}
+	if previous, exists := s.servers[server.ServerId]; exists {
+		stored = *previous
+		metrics = *previous.Metrics
+	} else {
+		stored = types.Server{ServerId: server.ServerId}
+	}
+
+	// 비어 있는 정의 값은 기존 값 유지
+	if server.ServerUrl != "" {
+		stored.ServerUrl = server.ServerUrl
+	}
+	if server.ServerType != "" {
+		stored.ServerType = server.ServerType
+	}
+	if server.Zone != "" {
+		stored.Zone = server.Zone
+	}
+	if server.Region != "" {
+		stored.Region = server.Region
+	}
+	if server.Labels != nil {
+		stored.Labels = server.Labels
+	}
+
+	now := time.Now()
+	sampledAt := report.Timestamp
+	if sampledAt.IsZero() {
+		sampledAt = now
+	}
+
+	metrics.CPUUsage = report.CPUUsage
+	metrics.MemoryUsage = report.MemoryUsage
+	metrics.Latency = report.Latency
+	metrics.Timestamp = sampledAt
+	// 메트릭 보고에는 서버 점수가 없으므로 이전 최적 서버 등록에서 받은 점수는 버림
+	metrics.PushedScore = nil
+	s.applyCountersLocked(server.ServerId, &metrics, report, sampledAt)
+
+	stored.Metrics = &metrics
+	stored.LastUpdated = now
+
+	breakdown := s.scoreService.Apply(&stored)
+	utils.Infof("서버 점수 계산: %s (계산: %.2f, 최종: %.2f, 감점: %d건, 요청률: %.2f/s, 에러율: %.2f%%)",
+		stored.ServerId, breakdown.ComputedScore, breakdown.Score, len(breakdown.Penalties), metrics.RequestRate, metrics.ErrorRate)
+
+	s.commitLocked(&stored, true)
+
+	updated := stored
+	return &updated, nil
+}
+
+// applyCountersLocked 누적 카운터 차이로 요청률과 에러율 계산 (mutex를 잡은 상태에서 호출)
+// 카운터가 줄어들면 서버 재시작으로 보고 현재 값을 재시작 이후 증가분으로 사용하며,
+// 그 사이 요청이 없어 에러율을 계산할 수 없으면 보고된 에러율을 사용합니다
+func (s *serverServiceImpl) applyCountersLocked(serverId string, metrics *types.Metrics, report *types.MetricsReport, sampledAt time.Time) {
+	last := s.counters[serverId]
+	hasCounters := report.TotalRequests > 0 || report.ErrorRequests > 0
+
+	// 카운터를 보내지 않는 서버는 보고된 에러율 사용
+	if !hasCounters && (last == nil || (last.total == 0 && last.errors == 0)) {
+		metrics.RequestRate = 0
+		metrics.ErrorRate = report.ErrorRate
+		s.counters[serverId] = &counterSample{at: sampledAt}
+		return
+	}
+
+	// 첫 수신이거나 이전 시각의 푸시는 차이를 계산할 수 없으므로 보고된 에러율 사용
+	if last == nil || !sampledAt.After(last.at) {
+		metrics.ErrorRate = report.ErrorRate
+		if last == nil {
+			s.counters[serverId] = &counterSample{total: report.TotalRequests, errors: report.ErrorRequests, at: sampledAt}
+		}
+		return
+	}
+
+	deltaTotal := report.TotalRequests - last.total
+	deltaErrors := report.ErrorRequests - last.errors
+	if deltaTotal < 0 || deltaErrors < 0 {
+		utils.Infof("요청 카운터 초기화 감지: %s (총 요청 %d -> %d, 에러 %d -> %d)",
+			serverId, last.total, report.TotalRequests, last.errors, report.ErrorRequests)
+		deltaTotal = report.TotalRequests
+		deltaErrors = report.ErrorRequests
+	}
+
+	metrics.RequestRate = float64(deltaTotal) / sampledAt.Sub(last.at).Seconds()
+	metrics.ErrorRate = report.ErrorRate
+	if deltaTotal > 0 {
+		metrics.ErrorRate = math.Min(float64(deltaErrors)/float64(deltaTotal)*100, 100)
+	}
+
+	s.counters[serverId] = &counterSample{total: report.TotalRequests, errors: report.ErrorRequests, at: sampledAt}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// TestUpdateMetricsCounters 누적 카운터 차이로 요청률과 에러율을 계산하고, 차이가 없으면 보고된 에러율을 쓰는지 확인
+func TestUpdateMetricsCounters(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	report := func(seconds int, total int64, errors int64, errorRate float64) *types.MetricsReport {
+		return &types.MetricsReport{
+			TotalRequests: total,
+			ErrorRequests: errors,
+			ErrorRate:     errorRate,
+			Timestamp:     start.Add(time.Duration(seconds) * time.Second),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		reports     []*types.MetricsReport
+		requestRate float64
+		errorRate   float64
+	}{
+		{"no counters", []*types.MetricsReport{report(0, 0, 0, 3)}, 0, 3},
+		{"first sample", []*types.MetricsReport{report(0, 100, 10, 4)}, 0, 4},
+		{"delta", []*types.MetricsReport{report(0, 100, 10, 0), report(10, 200, 15, 0)}, 10, 5},
+		{"restart", []*types.MetricsReport{report(0, 100, 10, 0), report(10, 50, 25, 0)}, 5, 50},
+		{"reset to zero", []*types.MetricsReport{report(0, 100, 10, 0), report(10, 0, 0, 7)}, 0, 7},
+		{"no traffic", []*types.MetricsReport{report(0, 100, 10, 0), report(10, 100, 10, 2)}, 0, 2},
+		{"out of order", []*types.MetricsReport{report(10, 100, 10, 0), report(0, 200, 20, 6)}, 0, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverService := newTestServerService(t, nil)
+
+			var updated *types.Server
+			for _, report := range tt.reports {
+				var err error
+				if updated, err = serverService.UpdateMetrics(&types.Server{ServerId: "a"}, report); err != nil {
+					t.Fatalf("메트릭 갱신 실패: %v", err)
+				}
+			}
+
+			if updated.Metrics.RequestRate != tt.requestRate || updated.Metrics.ErrorRate != tt.errorRate {
+				t.Errorf("요청률/에러율: %.2f/%.2f, 기대값: %.2f/%.2f",
+					updated.Metrics.RequestRate, updated.Metrics.ErrorRate, tt.requestRate, tt.errorRate)
+			}
+		})
+	}
+}
+
+// TestUpdateMetricsClearsPushedScore 최적 서버 등록으로 받은 점수가 이후 메트릭 보고에 남지 않는지 확인
+func TestUpdateMetricsClearsPushedScore(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+
+	pushed := 10.0
+	server := testServer("a", 0)
+	server.Metrics.PushedScore = &pushed
+	if err := serverService.AddServer(server); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+
+	updated, err := serverService.UpdateMetrics(&types.Server{ServerId: "a"}, &types.MetricsReport{})
+	if err != nil {
+		t.Fatalf("메트릭 갱신 실패: %v", err)
+	}
+	if updated.Metrics.PushedScore != nil {
+		t.Errorf("이전 푸시 점수가 남아 있습니다: %v", *updated.Metrics.PushedScore)
+	}
+}
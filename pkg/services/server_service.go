@@ -2,8 +2,13 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sh5080/ndns-router/pkg/configs"
@@ -12,98 +17,536 @@ import (
 	"github.com/sh5080/ndns-router/pkg/utils"
 )
 
-// ServerState는 서버의 현재 상태를 관리합니다
+// ServerState는 서버의 현재 상태를 관리합니다 (프록시 경로에서 잠금 없이 갱신)
 type ServerState struct {
-	ActiveRequests int       // 현재 활성 요청 수
-	LastUsedTime   time.Time // 마지막 사용 시간
-	mutex          sync.Mutex
+	activeRequests atomic.Int64 // 현재 활성 요청 수
+	lastUsedTime   atomic.Int64 // 마지막 사용 시간 (UnixNano)
+	proxyFailures  atomic.Int64 // 연속 프록시 실패 수
+	proxyReport    atomic.Bool  // 프록시 실패로 인한 상태 전환 처리 중 여부
+}
+
+// registrySnapshot은 특정 시점의 레지스트리 상태입니다
+// 한 번 게시된 스냅샷과 그 안의 서버 객체는 변경하지 않습니다
+type registrySnapshot struct {
+	servers map[string]*types.Server
+	states  map[string]*ServerState
+	group   *types.ServerGroup
 }
 
 // serverServiceImpl implements the ServerService interface
 type serverServiceImpl struct {
-	servers          map[string]*types.Server
-	serverStates     map[string]*ServerState
-	mutex            sync.RWMutex
-	stopCollection   chan struct{}
-	optimalServer    *types.OptimalServer // 최적 서버 정보 저장
-	serverlessServer *types.Server
-	serverGroup      *types.ServerGroup // 추가
-	serverGroupMutex sync.RWMutex       // 서버 그룹용 별도 뮤텍스
+	// 쓰기 전용 상태 (mutex로 보호)
+	mutex    sync.RWMutex
+	servers  map[string]*types.Server
+	states   map[string]*ServerState
+	drains   map[string]*types.DrainStatus // 드레인 중이거나 주차된 서버
+	statuses map[string]*statusState       // 상태 머신 입력과 전이 기록
+	counters map[string]*counterSample     // 마지막으로 받은 요청 카운터
+	dirty    map[string]struct{}           // 저장소에 아직 반영하지 않은 서버
+
+	// 읽기 경로는 게시된 스냅샷만 사용
+	snapshot atomic.Pointer[registrySnapshot]
+
+	// 저장소 쓰기는 레지스트리 잠금 밖에서 한 번에 하나씩 수행
+	persistMutex sync.Mutex
+
+	stopCollection chan struct{}
+	closeOnce      sync.Once
+	scoreService   interfaces.ScoreService
+	store          interfaces.ServerStore
+	eventBus       interfaces.EventBus
+	forceGood      *types.LabelSelector // 점수와 관계없이 양호 서버로 분류할 셀렉터
+	roundRobin     *utils.Generate
+	random         *utils.Calculate
+	randomMutex    sync.Mutex
 }
 
 // NewServerService creates a new instance of ServerService
-func NewServerService() (interfaces.ServerService, error) {
-	return &serverServiceImpl{
-		servers:          make(map[string]*types.Server),
-		serverStates:     make(map[string]*ServerState),
-		stopCollection:   make(chan struct{}),
-		optimalServer:    nil,
-		serverlessServer: nil,
-		serverGroup: &types.ServerGroup{
-			ExcellentServers: make([]*types.Server, 0),
-			GoodServers:      make([]*types.Server, 0),
-		},
-	}, nil
+// 저장소에 남아 있는 서버 목록으로 레지스트리를 복원합니다
+func NewServerService(scoreService interfaces.ScoreService, store interfaces.ServerStore, eventBus interfaces.EventBus) (interfaces.ServerService, error) {
+	if scoreService == nil {
+		return nil, errors.New("score service cannot be nil")
+	}
+	if store == nil {
+		return nil, errors.New("server store cannot be nil")
+	}
+	if eventBus == nil {
+		return nil, errors.New("event bus cannot be nil")
+	}
+
+	forceGood, err := utils.ParseLabelSelector(configs.GetConfig().Routing.ForceGoodSelector)
+	if err != nil {
+		return nil, fmt.Errorf("ROUTING_FORCE_GOOD_SELECTOR 파싱 실패: %w", err)
+	}
+
+	service := &serverServiceImpl{
+		servers:        make(map[string]*types.Server),
+		states:         make(map[string]*ServerState),
+		drains:         make(map[string]*types.DrainStatus),
+		statuses:       make(map[string]*statusState),
+		counters:       make(map[string]*counterSample),
+		dirty:          make(map[string]struct{}),
+		stopCollection: make(chan struct{}),
+		scoreService:   scoreService,
+		store:          store,
+		eventBus:       eventBus,
+		forceGood:      forceGood,
+		roundRobin:     utils.NewGenerate(),
+		random:         utils.NewCalculate(),
+	}
+
+	// 저장소에서 서버 목록 복원
+	restored, err := store.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("서버 목록 복원 실패: %w", err)
+	}
+	for _, server := range restored {
+		if server.ServerId == "" {
+			continue
+		}
+		if server.Metrics == nil {
+			server.Metrics = &types.Metrics{}
+		}
+		if !types.IsValidServerStatus(types.ServerStatus(server.CurrentStatus)) {
+			server.CurrentStatus = string(types.StatusUnknown)
+		}
+		service.servers[server.ServerId] = server
+		service.states[server.ServerId] = &ServerState{}
+		// 메트릭 갱신 중단 판단은 마지막 갱신 시간부터 시작
+		service.statusLocked(server.ServerId).lastMetrics = server.LastUpdated
+	}
+	service.publishLocked()
+	utils.Infof("저장소에서 서버 %d개 복원", len(service.servers))
+
+	// 드레인 진행 상황, 서버 상태 점검 시작
+	go service.watchDrains()
+	go service.watchStatuses()
+
+	return service, nil
+}
+
+// Close 드레인 진행 상황, 서버 상태 점검 중지
+func (s *serverServiceImpl) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopCollection)
+	})
+	return nil
+}
+
+// publishLocked 현재 레지스트리로 새 스냅샷을 만들어 게시 (mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) publishLocked() {
+	next := &registrySnapshot{
+		servers: make(map[string]*types.Server, len(s.servers)),
+		states:  make(map[string]*ServerState, len(s.states)),
+		group:   s.classifyServers(),
+	}
+	for serverId, server := range s.servers {
+		next.servers[serverId] = server
+	}
+	for serverId, state := range s.states {
+		next.states[serverId] = state
+	}
+
+	s.snapshot.Store(next)
 }
 
-// AddServer 새 서버 추가
+// classifyServers 드레인 중이 아닌 서버를 상태별로 분류 (mutex를 잡은 상태에서 호출)
+// 상태는 상태 머신이 점수, 헬스 체크, 프록시 실패 등으로 결정합니다
+func (s *serverServiceImpl) classifyServers() *types.ServerGroup {
+	group := &types.ServerGroup{
+		ExcellentServers: make([]*types.Server, 0),
+		GoodServers:      make([]*types.Server, 0),
+	}
+
+	serverIds := make([]string, 0, len(s.servers))
+	for serverId := range s.servers {
+		serverIds = append(serverIds, serverId)
+	}
+	sort.Strings(serverIds)
+
+	for _, serverId := range serverIds {
+		if _, draining := s.drains[serverId]; draining {
+			continue
+		}
+
+		server := s.servers[serverId]
+		switch types.ServerStatus(server.CurrentStatus) {
+		case types.StatusExcellent:
+			group.ExcellentServers = append(group.ExcellentServers, server)
+		case types.StatusGood:
+			group.GoodServers = append(group.GoodServers, server)
+		}
+	}
+
+	return group
+}
+
+// AddServer 새 서버 추가 (이미 있으면 기존 정보에 병합)
+// 비어 있는 위치, 라벨 등 정의 값과 메트릭은 기존 값을 유지합니다
 func (s *serverServiceImpl) AddServer(server *types.Server) error {
 	if server == nil {
 		return errors.New("server cannot be nil")
 	}
+	if err := utils.ValidateLabels(server.Labels); err != nil {
+		return err
+	}
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 드레인 중이거나 주차된 서버는 다시 등록하지 않음
+	if _, draining := s.drains[server.ServerId]; draining {
+		utils.Warnf("드레인 중인 서버 갱신 무시: %s", server.ServerId)
+		return types.ErrServerDraining
+	}
+
+	// 호출자가 넘긴 객체는 게시 후 변경될 수 있으므로 복사본을 저장
+	stored := *server
+	if existing, exists := s.servers[stored.ServerId]; exists {
+		// 비어 있는 정의 값은 기존 값 유지 (관리 API로 설정한 라벨, 위치 보존)
+		if stored.ServerUrl == "" {
+			stored.ServerUrl = existing.ServerUrl
+		}
+		if stored.ServerType == "" {
+			stored.ServerType = existing.ServerType
+		}
+		if stored.Zone == "" {
+			stored.Zone = existing.Zone
+		}
+		if stored.Region == "" {
+			stored.Region = existing.Region
+		}
+		if stored.Labels == nil {
+			stored.Labels = existing.Labels
+		}
+		if stored.Metrics == nil {
+			stored.Metrics = existing.Metrics
+		}
+	}
 
-	// 서버 메트릭스 초기화
-	if server.Metrics == nil {
-		server.Metrics = &types.Metrics{
+	hasMetrics := server.Metrics != nil
+	if hasMetrics {
+		// 메트릭이 있으면 라우터가 직접 점수 계산
+		metrics := *stored.Metrics
+		stored.Metrics = &metrics
+		breakdown := s.scoreService.Apply(&stored)
+		utils.Infof("서버 점수 계산: %s (계산: %.2f, 최종: %.2f, 감점: %d건)",
+			stored.ServerId, breakdown.ComputedScore, breakdown.Score, len(breakdown.Penalties))
+	} else if stored.Metrics == nil {
+		stored.Metrics = &types.Metrics{
 			Score: 0,
 		}
+	} else {
+		metrics := *stored.Metrics
+		stored.Metrics = &metrics
 	}
 
-	// 서버 추가
-	s.servers[server.ServerId] = server
-	utils.Infof("서버 추가됨: %s (%s)", server.ServerId, server.ServerUrl)
+	s.commitLocked(&stored, hasMetrics)
+
+	// 점수 계산 결과를 호출자에게도 반영
+	metrics := *stored.Metrics
+	server.Metrics = &metrics
+	server.CurrentStatus = stored.CurrentStatus
 
-	// 서버 분류
-	if server.Metrics.Score >= configs.ScoreExcellent {
-		s.serverGroup.ExcellentServers = append(s.serverGroup.ExcellentServers, server)
-	} else if server.Metrics.Score >= configs.ScoreGood {
-		s.serverGroup.GoodServers = append(s.serverGroup.GoodServers, server)
+	utils.Infof("서버 추가됨: %s (%s)", stored.ServerId, stored.ServerUrl)
+	return nil
+}
+
+// commitLocked 서버 상태를 결정해 레지스트리에 반영하고 이벤트 발행, 저장 표시 (mutex를 잡은 상태에서 호출)
+// 상태는 호출자가 지정하지 않고 상태 머신이 점수와 정책으로 결정합니다
+func (s *serverServiceImpl) commitLocked(stored *types.Server, hasMetrics bool) {
+	previous, existed := s.servers[stored.ServerId]
+
+	stored.CurrentStatus = string(types.StatusUnknown)
+	if existed {
+		stored.CurrentStatus = previous.CurrentStatus
+	}
+	if hasMetrics {
+		s.statusLocked(stored.ServerId).lastMetrics = time.Now()
+	}
+	if s.isForcedGood(stored) {
+		s.transitionLocked(stored, types.StatusGood, types.StatusTriggerPolicy, "ROUTING_FORCE_GOOD_SELECTOR")
+	} else if hasMetrics {
+		target := scoreStatus(types.ServerStatus(stored.CurrentStatus), stored.Metrics.Score, configs.GetConfig().Status.Hysteresis)
+		s.transitionLocked(stored, target, types.StatusTriggerScore, fmt.Sprintf("점수 %.2f", stored.Metrics.Score))
+	}
+
+	s.servers[stored.ServerId] = stored
+	if _, exists := s.states[stored.ServerId]; !exists {
+		s.states[stored.ServerId] = &ServerState{}
+	}
+	s.publishLocked()
+	s.emitChanges(previous, existed, stored)
+	s.markDirtyLocked(stored.ServerId)
+}
+
+// MergeServer 서버 정의만 갱신 (메트릭, 상태 등 런타임 정보는 유지)
+func (s *serverServiceImpl) MergeServer(server *types.Server) error {
+	if server == nil || server.ServerId == "" {
+		return errors.New("server id cannot be empty")
+	}
+	if err := utils.ValidateLabels(server.Labels); err != nil {
+		return err
 	}
 
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, draining := s.drains[server.ServerId]; draining {
+		return types.ErrServerDraining
+	}
+
+	previous, existed := s.servers[server.ServerId]
+	var merged types.Server
+	if existed {
+		merged = *previous
+	} else {
+		merged = types.Server{
+			ServerId:      server.ServerId,
+			CurrentStatus: string(types.StatusUnknown),
+			Metrics:       &types.Metrics{},
+		}
+		s.states[server.ServerId] = &ServerState{}
+	}
+
+	merged.ServerUrl = server.ServerUrl
+	merged.ServerType = server.ServerType
+	merged.Zone = server.Zone
+	merged.Region = server.Region
+	if server.Labels != nil {
+		merged.Labels = server.Labels
+	}
+	merged.LastUpdated = time.Now()
+	if s.isForcedGood(&merged) {
+		s.transitionLocked(&merged, types.StatusGood, types.StatusTriggerPolicy, "ROUTING_FORCE_GOOD_SELECTOR")
+	}
+
+	s.servers[merged.ServerId] = &merged
+	s.publishLocked()
+	s.emitChanges(previous, existed, &merged)
+	s.markDirtyLocked(merged.ServerId)
 	return nil
 }
 
+// isForcedGood 점수와 관계없이 양호 서버로 분류할 서버인지 확인
+func (s *serverServiceImpl) isForcedGood(server *types.Server) bool {
+	return !s.forceGood.Empty() && s.forceGood.MatchesServer(server)
+}
+
+// UpdateLabels 서버 라벨 변경 (replace가 true면 set으로 전체 교체)
+func (s *serverServiceImpl) UpdateLabels(serverId string, set map[string]string, remove []string, replace bool) (*types.Server, error) {
+	if err := utils.ValidateLabels(set); err != nil {
+		return nil, err
+	}
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, exists := s.servers[serverId]
+	if !exists {
+		return nil, types.ErrServerNotFound
+	}
+
+	labels := make(map[string]string, len(existing.Labels)+len(set))
+	if !replace {
+		for key, value := range existing.Labels {
+			labels[key] = value
+		}
+	}
+	for key, value := range set {
+		labels[key] = value
+	}
+	for _, key := range remove {
+		delete(labels, key)
+	}
+
+	// 게시된 서버 객체는 변경하지 않고 새 객체로 교체
+	updated := *existing
+	updated.Labels = labels
+
+	// 라벨에 따라 ROUTING_FORCE_GOOD_SELECTOR 적용 여부가 바뀌므로 같은 잠금 안에서 상태 재평가
+	if s.isForcedGood(&updated) {
+		s.transitionLocked(&updated, types.StatusGood, types.StatusTriggerPolicy, "ROUTING_FORCE_GOOD_SELECTOR")
+	} else if s.isForcedGood(existing) {
+		// 정책에서 빠지면 메트릭을 받은 적이 없으면 unknown, 있으면 마지막 점수로 재평가
+		target := types.StatusUnknown
+		trigger := types.StatusTriggerPolicy
+		if !s.statusLocked(serverId).lastMetrics.IsZero() {
+			target = scoreStatus(types.StatusUnknown, updated.Metrics.Score, 0)
+			trigger = types.StatusTriggerScore
+		}
+		s.transitionLocked(&updated, target, trigger, "라벨 변경으로 ROUTING_FORCE_GOOD_SELECTOR 해제")
+	}
+
+	s.servers[serverId] = &updated
+	s.publishLocked()
+	s.emitChanges(existing, true, &updated)
+	s.markDirtyLocked(serverId)
+
+	utils.Infof("서버 라벨 변경: %s %v", serverId, labels)
+	return &updated, nil
+}
+
+// GetServersBySelector 라벨 셀렉터와 일치하는 서버 조회
+func (s *serverServiceImpl) GetServersBySelector(selector *types.LabelSelector) ([]*types.Server, error) {
+	servers, err := s.GetAllServers()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*types.Server, 0, len(servers))
+	for _, server := range servers {
+		if selector.MatchesServer(server) {
+			matched = append(matched, server)
+		}
+	}
+	return matched, nil
+}
+
+// emitChanges 서버 갱신 전후를 비교해 레지스트리 이벤트 발행 (mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) emitChanges(previous *types.Server, existed bool, current *types.Server) {
+	now := time.Now()
+	if !existed {
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:     types.EventServerAdded,
+			ServerId: current.ServerId,
+			Time:     now,
+			Server:   current,
+		})
+		return
+	}
+
+	if previous.CurrentStatus != current.CurrentStatus {
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:           types.EventStatusChanged,
+			ServerId:       current.ServerId,
+			Time:           now,
+			Server:         current,
+			PreviousStatus: previous.CurrentStatus,
+			Reason:         s.lastTransitionReason(current.ServerId),
+		})
+	}
+
+	if !maps.Equal(previous.Labels, current.Labels) {
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:     types.EventLabelsChanged,
+			ServerId: current.ServerId,
+			Time:     now,
+			Server:   current,
+		})
+	}
+
+	previousScore := previous.Metrics.Score
+	if math.Abs(current.Metrics.Score-previousScore) >= configs.ScoreChangeThreshold {
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:          types.EventScoreChanged,
+			ServerId:      current.ServerId,
+			Time:          now,
+			Server:        current,
+			PreviousScore: &previousScore,
+		})
+	}
+}
+
 // RemoveServer 서버 제거
 func (s *serverServiceImpl) RemoveServer(serverId string) error {
+	return s.removeServer(serverId, "")
+}
+
+// removeServer 서버 제거 후 제거 이벤트 발행
+func (s *serverServiceImpl) removeServer(serverId string, reason string) error {
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.removeServerLocked(serverId, reason)
+	s.mutex.Unlock()
 
-	delete(s.servers, serverId)
+	s.persist()
 	utils.Infof("서버 제거됨: %s", serverId)
 	return nil
 }
 
+// removeServerLocked 레지스트리에서 서버 제거 (s.mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) removeServerLocked(serverId string, reason string) {
+	previous, existed := s.servers[serverId]
+	delete(s.servers, serverId)
+	delete(s.states, serverId)
+	delete(s.drains, serverId)
+	delete(s.statuses, serverId)
+	delete(s.counters, serverId)
+	s.publishLocked()
+	s.markDirtyLocked(serverId)
+
+	if existed {
+		s.eventBus.Publish(types.RegistryEvent{
+			Type:     types.EventServerRemoved,
+			ServerId: serverId,
+			Time:     time.Now(),
+			Server:   previous,
+			Reason:   reason,
+		})
+	}
+}
+
+// markDirtyLocked 저장소에 반영할 서버 표시 (mutex를 잡은 상태에서 호출)
+// 실제 저장은 잠금을 푼 뒤 persist가 수행하므로 느린 저장소가 레지스트리 갱신을 막지 않습니다
+func (s *serverServiceImpl) markDirtyLocked(serverId string) {
+	s.dirty[serverId] = struct{}{}
+}
+
+// persist 표시된 서버를 마지막 스냅샷 기준으로 저장하거나 삭제 (레지스트리 잠금 밖에서 호출)
+// 여러 갱신이 겹쳐도 저장소에는 항상 가장 최근 상태가 남습니다
+func (s *serverServiceImpl) persist() {
+	s.persistMutex.Lock()
+	defer s.persistMutex.Unlock()
+
+	s.mutex.Lock()
+	if len(s.dirty) == 0 {
+		s.mutex.Unlock()
+		return
+	}
+	dirty := s.dirty
+	s.dirty = make(map[string]struct{})
+	snapshot := s.snapshot.Load()
+	s.mutex.Unlock()
+
+	// 저장 실패는 레지스트리 갱신을 막지 않음
+	for serverId := range dirty {
+		server, exists := snapshot.servers[serverId]
+		if !exists {
+			if err := s.store.Delete(serverId); err != nil {
+				utils.Warnf("저장소에서 서버 삭제 실패: %s (%v)", serverId, err)
+			}
+			continue
+		}
+		if err := s.store.Save(server); err != nil {
+			utils.Warnf("서버 저장 실패: %s (%v)", serverId, err)
+		}
+	}
+}
+
 // GetAllServers 모든 서버 조회
 func (s *serverServiceImpl) GetAllServers() ([]*types.Server, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-	servers := make([]*types.Server, 0, len(s.servers))
+	snapshot := s.snapshot.Load()
+	servers := make([]*types.Server, 0, len(snapshot.servers))
 
-	for id, server := range s.servers {
-		utils.Infof("서버 정보 [%s]: %+v", id, *server)
+	for _, server := range snapshot.servers {
 		servers = append(servers, server)
 	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].ServerId < servers[j].ServerId
+	})
 
 	return servers, nil
 }
 
 // GetHealthyServers 건강한 서버만 조회
 func (s *serverServiceImpl) GetHealthyServers() ([]*types.Server, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	snapshot := s.snapshot.Load()
 
 	servers := make([]*types.Server, 0)
-	for _, server := range s.servers {
+	for _, server := range snapshot.servers {
 		if server.CurrentStatus == string(types.StatusGood) || server.CurrentStatus == string(types.StatusExcellent) {
 			servers = append(servers, server)
 		}
@@ -114,10 +557,7 @@ func (s *serverServiceImpl) GetHealthyServers() ([]*types.Server, error) {
 
 // GetServer 특정 서버 조회
 func (s *serverServiceImpl) GetServer(serverId string) (*types.Server, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	server, exists := s.servers[serverId]
+	server, exists := s.snapshot.Load().servers[serverId]
 	if !exists {
 		return nil, nil
 	}
@@ -125,91 +565,85 @@ func (s *serverServiceImpl) GetServer(serverId string) (*types.Server, error) {
 	return server, nil
 }
 
-// canUseServer checks if a server can be used based on concurrent requests and cooldown
-func (s *serverServiceImpl) canUseServer(serverId string) bool {
-	state, exists := s.serverStates[serverId]
+// BeginRequest 서버 요청 시작 기록
+func (s *serverServiceImpl) BeginRequest(serverId string) {
+	state, exists := s.snapshot.Load().states[serverId]
 	if !exists {
-		return true
+		return
 	}
 
-	state.mutex.Lock()
-	defer state.mutex.Unlock()
+	state.activeRequests.Add(1)
+	state.lastUsedTime.Store(time.Now().UnixNano())
+}
 
-	if state.ActiveRequests >= configs.MaxConcurrentRequests {
-		return false
+// EndRequest 서버 요청 종료 기록
+func (s *serverServiceImpl) EndRequest(serverId string) {
+	state, exists := s.snapshot.Load().states[serverId]
+	if !exists {
+		return
 	}
 
-	if time.Since(state.LastUsedTime) < configs.CooldownPeriod {
-		return false
+	if state.activeRequests.Add(-1) < 0 {
+		state.activeRequests.Store(0)
 	}
-
-	return true
 }
 
-// 서버 분류 함수 분리
-func (s *serverServiceImpl) classifyServers() {
-	s.serverGroupMutex.Lock()
-	defer s.serverGroupMutex.Unlock()
-
-	newGroup := &types.ServerGroup{
-		ExcellentServers: make([]*types.Server, 0),
-		GoodServers:      make([]*types.Server, 0),
+// GetActiveRequests 서버의 현재 활성 요청 수 조회
+func (s *serverServiceImpl) GetActiveRequests(serverId string) int {
+	state, exists := s.snapshot.Load().states[serverId]
+	if !exists {
+		return 0
 	}
 
-	s.mutex.RLock()
-	for serverId, server := range s.servers {
-		if !s.canUseServer(server.ServerId) {
-			utils.Infof("사용 불가능한 서버: %s", serverId)
-			continue
-		}
+	return int(state.activeRequests.Load())
+}
 
-		if server.ServerType == "wsl" {
-			server.CurrentStatus = string(types.StatusGood)
-			newGroup.GoodServers = append(newGroup.GoodServers, server)
-			continue
-		}
+// ExplainScores 서버별 점수 계산 내역 조회 (dry-run)
+func (s *serverServiceImpl) ExplainScores() ([]*types.ScoreBreakdown, error) {
+	servers, err := s.GetAllServers()
+	if err != nil {
+		return nil, err
+	}
 
-		if server.Metrics.Score >= configs.ScoreExcellent {
-			newGroup.ExcellentServers = append(newGroup.ExcellentServers, server)
-		} else if server.Metrics.Score >= configs.ScoreGood {
-			newGroup.GoodServers = append(newGroup.GoodServers, server)
-		}
+	breakdowns := make([]*types.ScoreBreakdown, 0, len(servers))
+	for _, server := range servers {
+		breakdown := s.scoreService.Calculate(server.Metrics)
+		breakdown.ServerId = server.ServerId
+		breakdowns = append(breakdowns, breakdown)
 	}
-	s.mutex.RUnlock()
 
-	s.serverGroup = newGroup
-	utils.Infof("서버 분류 완료 - 최상위 서버: %d개, 양호 서버: %d개",
-		len(newGroup.ExcellentServers), len(newGroup.GoodServers))
+	return breakdowns, nil
 }
 
+// GetServerGroup 현재 스냅샷의 서버 그룹 반환 (잠금 없음)
+// 서버 목록은 공유되므로 호출자는 반환된 슬라이스를 변경하면 안 됩니다
 func (s *serverServiceImpl) GetServerGroup() *types.ServerGroup {
-	s.serverGroupMutex.Lock()
-	defer s.serverGroupMutex.Unlock()
-
-	if s.serverGroup == nil {
-		s.serverGroup = &types.ServerGroup{
-			ExcellentServers: make([]*types.Server, 0),
-			GoodServers:      make([]*types.Server, 0),
-		}
-	}
+	group := *s.snapshot.Load().group
 
 	// 0.0 ~ 1.0 사이의 랜덤 값 생성
-	random := utils.NewCalculate().RandomFloat64()
+	s.randomMutex.Lock()
+	random := s.random.RandomFloat64()
+	s.randomMutex.Unlock()
 
-	// 서버리스 강제 설정
-	s.serverGroup.ForceServerless = random < configs.ServerlessForceRatio
+	// 서버리스 강제 설정 (요청별 복사본에만 반영)
+	group.ForceServerless = random < configs.ServerlessForceRatio
 
-	if s.serverGroup.ForceServerless {
+	if group.ForceServerless {
 		utils.Infof("서버리스 강제 사용 비율 체크: %.2f (기준: %.2f) -> 서버리스 강제",
 			random, configs.ServerlessForceRatio)
 	}
 
-	return s.serverGroup
+	return &group
 }
 
 func (s *serverServiceImpl) GetServerlessServer() *types.Server {
-	serverIndex := utils.NewGenerate().NextRoundRobinIndex(len(configs.GetConfig().Serverless.Servers))
-	selectedServer := configs.GetConfig().Serverless.Servers[serverIndex]
+	servers := configs.GetConfig().Serverless.Servers
+	if len(servers) == 0 {
+		return nil
+	}
+
+	serverIndex := s.roundRobin.NextRoundRobinIndex(len(servers))
+	selectedServer := servers[serverIndex]
 
 	// URL에서 서브도메인만 추출
 	serverDomain := strings.Split(strings.Replace(selectedServer, "https://", "", 1), ".")[0] // api3.ndns.site -> api3
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// newTestServerService 메모리 저장소를 사용하는 서버 서비스 생성
+func newTestServerService(t *testing.T, store interfaces.ServerStore) interfaces.ServerService {
+	t.Helper()
+
+	scoreService, err := NewScoreService()
+	if err != nil {
+		t.Fatalf("점수 서비스 생성 실패: %v", err)
+	}
+	if store == nil {
+		store = NewMemoryServerStore()
+	}
+	serverService, err := NewServerService(scoreService, store, NewEventBus())
+	if err != nil {
+		t.Fatalf("서버 서비스 생성 실패: %v", err)
+	}
+	t.Cleanup(func() { serverService.Close() })
+	return serverService
+}
+
+// testServer 주어진 CPU 사용률로 메트릭을 가진 서버 생성 (낮을수록 높은 점수)
+func testServer(serverId string, cpuUsage float64) *types.Server {
+	return &types.Server{
+		ServerId:  serverId,
+		ServerUrl: "http://" + serverId,
+		Metrics:   &types.Metrics{CPUUsage: cpuUsage},
+	}
+}
+
+func TestServerServiceAddRemoveSelect(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+
+	if err := serverService.AddServer(testServer("a", 0)); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+	group := serverService.GetServerGroup()
+	if len(group.ExcellentServers) != 1 || group.ExcellentServers[0].ServerId != "a" {
+		t.Fatalf("최상위 서버 목록에 a가 있어야 합니다: %+v", group.ExcellentServers)
+	}
+
+	if err := serverService.RemoveServer("a"); err != nil {
+		t.Fatalf("서버 제거 실패: %v", err)
+	}
+	if server, _ := serverService.GetServer("a"); server != nil {
+		t.Fatalf("제거된 서버가 조회됩니다: %+v", server)
+	}
+	if group := serverService.GetServerGroup(); len(group.ExcellentServers)+len(group.GoodServers) != 0 {
+		t.Fatalf("제거 후 서버 그룹이 비어 있어야 합니다: %+v", group)
+	}
+
+	// 이전에 읽은 스냅샷은 이후 변경의 영향을 받지 않음
+	if len(group.ExcellentServers) != 1 {
+		t.Fatalf("게시된 스냅샷이 변경되었습니다: %+v", group.ExcellentServers)
+	}
+}
+
+// TestServerServiceConcurrentAccess 푸시, 제거, 선택을 동시에 수행 (go test -race로 실행)
+func TestServerServiceConcurrentAccess(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+
+	const (
+		writers    = 4
+		readers    = 8
+		iterations = 200
+		servers    = 10
+	)
+
+	var wg sync.WaitGroup
+	for writer := 0; writer < writers; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				serverId := fmt.Sprintf("server-%d", (writer+i)%servers)
+				if i%5 == 4 {
+					serverService.RemoveServer(serverId)
+					continue
+				}
+				if err := serverService.AddServer(testServer(serverId, float64(i%100))); err != nil {
+					t.Errorf("서버 추가 실패: %v", err)
+					return
+				}
+			}
+		}(writer)
+	}
+
+	for reader := 0; reader < readers; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				group := serverService.GetServerGroup()
+				for _, server := range group.ExcellentServers {
+					if server.CurrentStatus != string(types.StatusExcellent) {
+						t.Errorf("최상위 목록에 %s 상태 서버: %s", server.CurrentStatus, server.ServerId)
+						return
+					}
+					serverService.BeginRequest(server.ServerId)
+					_ = server.Metrics.Score
+					serverService.ReportProxyResult(server.ServerId, nil)
+					serverService.EndRequest(server.ServerId)
+				}
+				for _, server := range group.GoodServers {
+					if server.CurrentStatus != string(types.StatusGood) {
+						t.Errorf("양호 목록에 %s 상태 서버: %s", server.CurrentStatus, server.ServerId)
+						return
+					}
+				}
+				if _, err := serverService.GetAllServers(); err != nil {
+					t.Errorf("서버 목록 조회 실패: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 마지막 스냅샷의 서버 그룹은 레지스트리에 남은 서버만 포함해야 함
+	all, err := serverService.GetAllServers()
+	if err != nil {
+		t.Fatalf("서버 목록 조회 실패: %v", err)
+	}
+	registered := make(map[string]bool, len(all))
+	for _, server := range all {
+		registered[server.ServerId] = true
+	}
+	group := serverService.GetServerGroup()
+	for _, servers := range [][]*types.Server{group.ExcellentServers, group.GoodServers} {
+		for _, server := range servers {
+			if !registered[server.ServerId] {
+				t.Fatalf("제거된 서버가 서버 그룹에 남아 있습니다: %s", server.ServerId)
+			}
+		}
+	}
+}
+
+// TestServerServiceAddServerMerges 이미 등록된 서버를 다시 추가하면 보내지 않은 위치, 라벨, 메트릭을 유지하는지 확인
+func TestServerServiceAddServerMerges(t *testing.T) {
+	serverService := newTestServerService(t, nil)
+
+	server := testServer("a", 10)
+	server.Zone = "zone-a"
+	server.Region = "region-1"
+	server.Labels = map[string]string{"tier": "gpu"}
+	if err := serverService.AddServer(server); err != nil {
+		t.Fatalf("서버 추가 실패: %v", err)
+	}
+
+	// 위치, 라벨, 메트릭 없이 URL만 바꿔 다시 추가
+	if err := serverService.AddServer(&types.Server{ServerId: "a", ServerUrl: "http://a-2"}); err != nil {
+		t.Fatalf("서버 재추가 실패: %v", err)
+	}
+
+	merged, _ := serverService.GetServer("a")
+	if merged.ServerUrl != "http://a-2" || merged.Zone != "zone-a" || merged.Region != "region-1" || merged.Labels["tier"] != "gpu" {
+		t.Fatalf("정의 값이 병합되지 않았습니다: %+v", merged)
+	}
+	if merged.Metrics.CPUUsage != 10 || merged.Metrics.Score == 0 {
+		t.Fatalf("메트릭이 초기화되었습니다: %+v", merged.Metrics)
+	}
+	if types.ServerStatus(merged.CurrentStatus) != types.StatusExcellent {
+		t.Fatalf("상태가 유지되어야 합니다: %s", merged.CurrentStatus)
+	}
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// statusState는 서버 상태 머신의 입력 누적 값과 전이 기록입니다 (mutex로 보호)
+type statusState struct {
+	probeFailures  int
+	probeSuccesses int
+	override       *types.StatusOverride
+	lastMetrics    time.Time
+	transitions    []types.StatusTransition
+}
+
+// statusLocked 서버 상태 머신 상태 조회 (없으면 생성, mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) statusLocked(serverId string) *statusState {
+	state, exists := s.statuses[serverId]
+	if !exists {
+		state = &statusState{}
+		s.statuses[serverId] = state
+	}
+	return state
+}
+
+// transitionLocked 아직 게시되지 않은 서버 객체의 상태를 전이하고 기록 (mutex를 잡은 상태에서 호출)
+// 관리자 지정 상태가 있으면 관리자 입력 외에는 무시하며, 허용되지 않은 전이는 가능한 상태로 제한합니다
+func (s *serverServiceImpl) transitionLocked(server *types.Server, to types.ServerStatus, trigger types.StatusTrigger, reason string) bool {
+	from := types.ServerStatus(server.CurrentStatus)
+	if !types.IsValidServerStatus(from) {
+		from = types.StatusUnknown
+	}
+
+	state := s.statusLocked(server.ServerId)
+	if trigger != types.StatusTriggerAdmin {
+		if state.override != nil {
+			return false
+		}
+		to = constrainTransition(from, to)
+	}
+
+	server.CurrentStatus = string(to)
+	if from == to {
+		return false
+	}
+
+	// 비정상 상태를 벗어나면 실패 누적 초기화
+	if from == types.StatusUnhealthy {
+		state.probeFailures = 0
+		if serverState, exists := s.states[server.ServerId]; exists {
+			serverState.proxyFailures.Store(0)
+		}
+	}
+
+	state.transitions = append(state.transitions, types.StatusTransition{
+		From:    from,
+		To:      to,
+		Trigger: trigger,
+		Reason:  reason,
+		Time:    time.Now(),
+	})
+	if limit := configs.GetConfig().Status.TransitionHistory; limit > 0 && len(state.transitions) > limit {
+		state.transitions = append([]types.StatusTransition(nil), state.transitions[len(state.transitions)-limit:]...)
+	}
+
+	utils.Infof("서버 상태 전이: %s %s -> %s (%s: %s)", server.ServerId, from, to, trigger, reason)
+	return true
+}
+
+// applyStatusLocked 등록된 서버의 상태를 전이하고 새 스냅샷 게시 (mutex를 잡은 상태에서 호출, 저장은 잠금을 푼 뒤 persist로 수행)
+func (s *serverServiceImpl) applyStatusLocked(serverId string, to types.ServerStatus, trigger types.StatusTrigger, reason string) error {
+	previous, exists := s.servers[serverId]
+	if !exists {
+		return types.ErrServerNotFound
+	}
+
+	// 게시된 서버 객체는 변경하지 않고 새 객체로 교체
+	updated := *previous
+	if !s.transitionLocked(&updated, to, trigger, reason) {
+		return nil
+	}
+
+	s.servers[serverId] = &updated
+	s.publishLocked()
+	s.emitChanges(previous, true, &updated)
+	s.markDirtyLocked(serverId)
+	return nil
+}
+
+// lastTransitionReason 마지막 상태 전이 사유 (mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) lastTransitionReason(serverId string) string {
+	state, exists := s.statuses[serverId]
+	if !exists || len(state.transitions) == 0 {
+		return ""
+	}
+
+	last := state.transitions[len(state.transitions)-1]
+	if last.Reason == "" {
+		return string(last.Trigger)
+	}
+	return fmt.Sprintf("%s: %s", last.Trigger, last.Reason)
+}
+
+// ReportProbe 헬스 체크 결과 반영 (연속 실패 시 비정상, 비정상 상태에서 연속 성공 시 warning으로 복귀)
+// 메트릭을 한 번도 받지 못한 unknown 서버(디스커버리로 발견한 서버 등)는 연속 성공 시 good으로 승격합니다
+func (s *serverServiceImpl) ReportProbe(serverId string, healthy bool, reason string) error {
+	cfg := configs.GetConfig().Status
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	server, exists := s.servers[serverId]
+	if !exists {
+		return types.ErrServerNotFound
+	}
+
+	state := s.statusLocked(serverId)
+	if healthy {
+		state.probeFailures = 0
+		state.probeSuccesses++
+		if state.probeSuccesses < cfg.ProbeSuccessThreshold {
+			return nil
+		}
+		switch {
+		case server.CurrentStatus == string(types.StatusUnhealthy):
+			return s.applyStatusLocked(serverId, types.StatusWarning, types.StatusTriggerProbe,
+				fmt.Sprintf("연속 %d회 성공", state.probeSuccesses))
+		case server.CurrentStatus == string(types.StatusUnknown) && state.lastMetrics.IsZero():
+			return s.applyStatusLocked(serverId, types.StatusGood, types.StatusTriggerProbe,
+				fmt.Sprintf("메트릭 없이 연속 %d회 성공", state.probeSuccesses))
+		}
+		return nil
+	}
+
+	state.probeSuccesses = 0
+	state.probeFailures++
+	if state.probeFailures >= cfg.ProbeFailureThreshold {
+		return s.applyStatusLocked(serverId, types.StatusUnhealthy, types.StatusTriggerProbe,
+			fmt.Sprintf("연속 %d회 실패: %s", state.probeFailures, reason))
+	}
+	return nil
+}
+
+// ReportProxyResult 프록시 요청 결과 반영 (요청 경로에서는 잠금을 잡지 않음)
+// 기준 횟수를 넘으면 상태 전환과 저장은 별도 고루틴에서 서버마다 한 번에 하나만 처리합니다
+func (s *serverServiceImpl) ReportProxyResult(serverId string, err error) {
+	snapshot := s.snapshot.Load()
+	serverState, exists := snapshot.states[serverId]
+	if !exists {
+		return
+	}
+
+	if err == nil {
+		serverState.proxyFailures.Store(0)
+		return
+	}
+
+	failures := serverState.proxyFailures.Add(1)
+	if failures < int64(configs.GetConfig().Status.ProxyFailureThreshold) {
+		return
+	}
+	if server, exists := snapshot.servers[serverId]; exists && server.CurrentStatus == string(types.StatusUnhealthy) {
+		return
+	}
+	if !serverState.proxyReport.CompareAndSwap(false, true) {
+		return
+	}
+
+	reason := fmt.Sprintf("연속 %d회 실패: %v", failures, err)
+	go func() {
+		defer serverState.proxyReport.Store(false)
+		defer s.persist()
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.applyStatusLocked(serverId, types.StatusUnhealthy, types.StatusTriggerProxy, reason)
+	}()
+}
+
+// MarkStale 메트릭을 받을 수 없는 서버를 unknown으로 전환 (다음 메트릭 수신 시 점수로 재평가)
+func (s *serverServiceImpl) MarkStale(serverId string, reason string) error {
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.applyStatusLocked(serverId, types.StatusUnknown, types.StatusTriggerStale, reason)
+}
+
+// SetStatusOverride 관리자 상태 지정 (duration이 0이면 해제할 때까지 유지)
+func (s *serverServiceImpl) SetStatusOverride(serverId string, status types.ServerStatus, reason string, duration time.Duration) (*types.StatusOverride, error) {
+	if !types.IsValidServerStatus(status) {
+		return nil, fmt.Errorf("알 수 없는 서버 상태: %s", status)
+	}
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.servers[serverId]; !exists {
+		return nil, types.ErrServerNotFound
+	}
+
+	now := time.Now()
+	override := &types.StatusOverride{
+		Status: status,
+		Reason: reason,
+		SetAt:  now,
+	}
+	if duration > 0 {
+		expiresAt := now.Add(duration)
+		override.ExpiresAt = &expiresAt
+	}
+
+	s.statusLocked(serverId).override = override
+	if err := s.applyStatusLocked(serverId, status, types.StatusTriggerAdmin, reason); err != nil {
+		return nil, err
+	}
+
+	copied := *override
+	return &copied, nil
+}
+
+// ClearStatusOverride 관리자 상태 지정 해제 후 마지막 점수로 다시 평가
+func (s *serverServiceImpl) ClearStatusOverride(serverId string) error {
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.clearOverrideLocked(serverId, "관리자 지정 해제")
+}
+
+// clearOverrideLocked 관리자 상태 지정 해제 (mutex를 잡은 상태에서 호출)
+func (s *serverServiceImpl) clearOverrideLocked(serverId string, reason string) error {
+	server, exists := s.servers[serverId]
+	if !exists {
+		return types.ErrServerNotFound
+	}
+
+	state := s.statusLocked(serverId)
+	if state.override == nil {
+		return types.ErrNoStatusOverride
+	}
+	state.override = nil
+
+	// 메트릭을 받은 적이 없으면 unknown, 있으면 마지막 점수로 재평가
+	target := types.StatusUnknown
+	trigger := types.StatusTriggerAdmin
+	if !state.lastMetrics.IsZero() {
+		target = scoreStatus(types.StatusUnknown, server.Metrics.Score, 0)
+		trigger = types.StatusTriggerScore
+	}
+	if s.isForcedGood(server) {
+		target = types.StatusGood
+		trigger = types.StatusTriggerPolicy
+	}
+
+	return s.applyStatusLocked(serverId, target, trigger, reason)
+}
+
+// GetStatusInfo 서버 상태 머신 현황과 전이 기록 조회
+func (s *serverServiceImpl) GetStatusInfo(serverId string) (*types.ServerStatusInfo, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	server, exists := s.servers[serverId]
+	if !exists {
+		return nil, types.ErrServerNotFound
+	}
+
+	info := &types.ServerStatusInfo{
+		ServerId:    serverId,
+		Status:      types.ServerStatus(server.CurrentStatus),
+		Transitions: make([]types.StatusTransition, 0),
+	}
+	if serverState, exists := s.states[serverId]; exists {
+		info.ProxyFailures = int(serverState.proxyFailures.Load())
+	}
+	if state, exists := s.statuses[serverId]; exists {
+		info.ProbeFailures = state.probeFailures
+		info.ProbeSuccesses = state.probeSuccesses
+		info.Transitions = append(info.Transitions, state.transitions...)
+		if state.override != nil {
+			override := *state.override
+			info.Override = &override
+		}
+		if !state.lastMetrics.IsZero() {
+			lastMetrics := state.lastMetrics
+			info.LastMetrics = &lastMetrics
+		}
+	}
+
+	return info, nil
+}
+
+// watchStatuses 주기적으로 메트릭 갱신 중단과 관리자 지정 만료 점검
+func (s *serverServiceImpl) watchStatuses() {
+	ticker := time.NewTicker(configs.StatusCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCollection:
+			return
+		case <-ticker.C:
+			s.checkStatuses()
+		}
+	}
+}
+
+// checkStatuses 만료된 관리자 지정 해제, 메트릭이 끊긴 서버는 unknown으로 전환
+func (s *serverServiceImpl) checkStatuses() {
+	staleAfter := configs.GetConfig().Status.StaleAfter
+	now := time.Now()
+
+	defer s.persist()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for serverId, state := range s.statuses {
+		server, exists := s.servers[serverId]
+		if !exists {
+			continue
+		}
+
+		if state.override != nil && state.override.ExpiresAt != nil && now.After(*state.override.ExpiresAt) {
+			s.clearOverrideLocked(serverId, "관리자 지정 만료")
+			continue
+		}
+
+		// 비정상 서버는 unknown으로 바꾸지 않음 (warning을 거쳐 복귀)
+		if staleAfter <= 0 || state.lastMetrics.IsZero() ||
+			server.CurrentStatus == string(types.StatusUnknown) || server.CurrentStatus == string(types.StatusUnhealthy) {
+			continue
+		}
+		if elapsed := now.Sub(state.lastMetrics); elapsed > staleAfter {
+			s.applyStatusLocked(serverId, types.StatusUnknown, types.StatusTriggerStale,
+				fmt.Sprintf("%s 동안 메트릭 없음", elapsed.Truncate(time.Second)))
+		}
+	}
+}
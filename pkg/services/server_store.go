@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// 저장소 종류
+const (
+	StoreTypeMemory = "memory"
+	StoreTypeFile   = "file"
+	StoreTypeRedis  = "redis"
+)
+
+// NewServerStore creates a ServerStore based on the configuration
+func NewServerStore() (interfaces.ServerStore, error) {
+	cfg := configs.GetConfig()
+
+	storeType := cfg.Store.Type
+	if cfg.Redis.Enabled {
+		storeType = StoreTypeRedis
+	}
+
+	switch storeType {
+	case StoreTypeMemory, "":
+		utils.Info("서버 저장소: 메모리")
+		return NewMemoryServerStore(), nil
+	case StoreTypeFile:
+		utils.Infof("서버 저장소: 파일 (%s)", cfg.Store.FilePath)
+		return NewFileServerStore(cfg.Store.FilePath)
+	case StoreTypeRedis:
+		utils.Infof("서버 저장소: Redis (%s, db: %d)", cfg.Redis.Addr, cfg.Redis.DB)
+		return NewRedisServerStore(redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		}))
+	}
+
+	return nil, fmt.Errorf("알 수 없는 저장소 종류: %s", storeType)
+}
+
+// memoryServerStore는 프로세스 메모리에 서버 정보를 보관합니다 (재시작 시 초기화)
+type memoryServerStore struct {
+	servers map[string]types.Server
+	mutex   sync.RWMutex
+}
+
+// NewMemoryServerStore creates a new in-memory ServerStore
+func NewMemoryServerStore() interfaces.ServerStore {
+	return &memoryServerStore{
+		servers: make(map[string]types.Server),
+	}
+}
+
+func (m *memoryServerStore) Save(server *types.Server) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.servers[server.ServerId] = *server
+	return nil
+}
+
+func (m *memoryServerStore) Delete(serverId string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.servers, serverId)
+	return nil
+}
+
+func (m *memoryServerStore) LoadAll() ([]*types.Server, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	servers := make([]*types.Server, 0, len(m.servers))
+	for _, server := range m.servers {
+		copied := server
+		servers = append(servers, &copied)
+	}
+	return servers, nil
+}
+
+func (m *memoryServerStore) Close() error {
+	return nil
+}
+
+// fileServerStore는 서버 목록 전체를 JSON 파일 스냅샷으로 저장합니다
+type fileServerStore struct {
+	path    string
+	servers map[string]*types.Server
+	mutex   sync.Mutex
+}
+
+// NewFileServerStore creates a ServerStore backed by a JSON snapshot file
+func NewFileServerStore(path string) (interfaces.ServerStore, error) {
+	if path == "" {
+		return nil, errors.New("파일 저장소 경로가 비어 있습니다")
+	}
+
+	store := &fileServerStore{
+		path:    path,
+		servers: make(map[string]*types.Server),
+	}
+
+	// 기존 스냅샷 읽기
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("스냅샷 파일 읽기 실패: %w", err)
+	}
+	if len(data) > 0 {
+		var servers []*types.Server
+		if err := json.Unmarshal(data, &servers); err != nil {
+			return nil, fmt.Errorf("스냅샷 파일 파싱 실패: %w", err)
+		}
+		for _, server := range servers {
+			store.servers[server.ServerId] = server
+		}
+	}
+
+	return store, nil
+}
+
+func (f *fileServerStore) Save(server *types.Server) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	copied := *server
+	f.servers[server.ServerId] = &copied
+	return f.flushLocked()
+}
+
+func (f *fileServerStore) Delete(serverId string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, exists := f.servers[serverId]; !exists {
+		return nil
+	}
+	delete(f.servers, serverId)
+	return f.flushLocked()
+}
+
+func (f *fileServerStore) LoadAll() ([]*types.Server, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	servers := make([]*types.Server, 0, len(f.servers))
+	for _, server := range f.servers {
+		copied := *server
+		servers = append(servers, &copied)
+	}
+	return servers, nil
+}
+
+func (f *fileServerStore) Close() error {
+	return nil
+}
+
+// flushLocked 임시 파일에 쓴 뒤 교체하여 스냅샷을 원자적으로 저장
+func (f *fileServerStore) flushLocked() error {
+	servers := make([]*types.Server, 0, len(f.servers))
+	for _, server := range f.servers {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].ServerId < servers[j].ServerId
+	})
+
+	data, err := json.MarshalIndent(servers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("스냅샷 직렬화 실패: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("스냅샷 디렉토리 생성 실패: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("임시 파일 생성 실패: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("스냅샷 쓰기 실패: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("스냅샷 쓰기 실패: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// SSE 메시지 발행/구독 통로 종류
+const (
+	SseBrokerMemory = "memory"
+	SseBrokerRedis  = "redis"
+)
+
+// NewSseBroker creates an SseBroker based on the configuration
+func NewSseBroker() (interfaces.SseBroker, error) {
+	cfg := configs.GetConfig()
+
+	switch cfg.Sse.Broker {
+	case SseBrokerMemory, "":
+		utils.Info("SSE 메시지 통로: 메모리 (단일 인스턴스)")
+		return NewMemorySseBroker(), nil
+	case SseBrokerRedis:
+		utils.Infof("SSE 메시지 통로: Redis pub/sub (%s, 채널: %s)", cfg.Redis.Addr, cfg.Sse.BrokerChannel)
+		return NewRedisSseBroker(redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		}), cfg.Sse.BrokerChannel)
+	}
+
+	return nil, fmt.Errorf("알 수 없는 SSE 메시지 통로 종류: %s", cfg.Sse.Broker)
+}
+
+// memorySseBroker는 같은 프로세스의 구독자에게 바로 전달합니다 (단일 인스턴스용)
+type memorySseBroker struct {
+	mutex    sync.RWMutex
+	handlers map[int]func(*types.SseBrokerMessage)
+	nextId   int
+}
+
+// NewMemorySseBroker creates an in-process SseBroker
+func NewMemorySseBroker() interfaces.SseBroker {
+	return &memorySseBroker{
+		handlers: make(map[int]func(*types.SseBrokerMessage)),
+	}
+}
+
+// Publish 구독자에게 순서대로 바로 전달 (전달이 끝난 뒤 반환)
+func (m *memorySseBroker) Publish(_ context.Context, message *types.SseBrokerMessage) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for _, handler := range m.handlers {
+		handler(message)
+	}
+	return nil
+}
+
+func (m *memorySseBroker) Subscribe(ctx context.Context, handler func(*types.SseBrokerMessage)) error {
+	m.mutex.Lock()
+	id := m.nextId
+	m.nextId++
+	m.handlers[id] = handler
+	m.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mutex.Lock()
+		delete(m.handlers, id)
+		m.mutex.Unlock()
+	}()
+	return nil
+}
+
+func (m *memorySseBroker) Close() error {
+	return nil
+}
+
+// redisSseBroker는 Redis pub/sub 채널로 모든 라우터 인스턴스에 메시지를 전달합니다
+type redisSseBroker struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisSseBroker creates an SseBroker backed by Redis pub/sub
+// 테스트에서는 인프로세스 Redis 대체 서버에 연결한 클라이언트를 넘길 수 있습니다
+func NewRedisSseBroker(client redis.UniversalClient, channel string) (interfaces.SseBroker, error) {
+	if client == nil {
+		return nil, errors.New("redis client cannot be nil")
+	}
+	if channel == "" {
+		return nil, errors.New("SSE 메시지 채널 이름이 비어 있습니다")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configs.StoreTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("redis 연결 실패: %w", err)
+	}
+
+	return &redisSseBroker{client: client, channel: channel}, nil
+}
+
+func (r *redisSseBroker) Publish(ctx context.Context, message *types.SseBrokerMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("SSE 메시지 직렬화 실패: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, configs.StoreTimeout)
+	defer cancel()
+
+	if err := r.client.Publish(ctx, r.channel, payload).Err(); err != nil {
+		return fmt.Errorf("SSE 메시지 발행 실패: %w", err)
+	}
+	return nil
+}
+
+// Subscribe 채널 구독이 확인된 뒤 반환하며, 받은 메시지는 도착 순서대로 handler에 전달
+// 연결이 끊기면 클라이언트가 다시 연결해 구독을 이어갑니다
+func (r *redisSseBroker) Subscribe(ctx context.Context, handler func(*types.SseBrokerMessage)) error {
+	pubsub := r.client.Subscribe(ctx, r.channel)
+
+	receiveCtx, cancel := context.WithTimeout(ctx, configs.StoreTimeout)
+	defer cancel()
+	if _, err := pubsub.Receive(receiveCtx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("redis 채널 구독 실패: %w", err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case received, ok := <-messages:
+				if !ok {
+					return
+				}
+				message := &types.SseBrokerMessage{}
+				if err := json.Unmarshal([]byte(received.Payload), message); err != nil {
+					utils.Warnf("[SSE] 잘못된 SSE 메시지 무시 (%v)", err)
+					continue
+				}
+				handler(message)
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *redisSseBroker) Close() error {
+	return r.client.Close()
+}
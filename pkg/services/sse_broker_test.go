@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+const testSseChannel = "ndns:router:sse:test"
+
+// newTestRedisSseBroker 인프로세스 Redis 대체 서버에 연결한 메시지 통로 생성 (인스턴스 하나에 해당)
+func newTestRedisSseBroker(t *testing.T, server *miniredis.Miniredis) interfaces.SseBroker {
+	t.Helper()
+
+	broker, err := NewRedisSseBroker(redis.NewClient(&redis.Options{Addr: server.Addr()}), testSseChannel)
+	if err != nil {
+		t.Fatalf("Redis 메시지 통로 생성 실패: %v", err)
+	}
+	t.Cleanup(func() { broker.Close() })
+	return broker
+}
+
+// subscribeTestSseBroker 받은 메시지를 채널로 넘기는 구독 시작
+func subscribeTestSseBroker(t *testing.T, ctx context.Context, broker interfaces.SseBroker) <-chan *types.SseBrokerMessage {
+	t.Helper()
+
+	received := make(chan *types.SseBrokerMessage, 10)
+	if err := broker.Subscribe(ctx, func(message *types.SseBrokerMessage) {
+		received <- message
+	}); err != nil {
+		t.Fatalf("구독 실패: %v", err)
+	}
+	return received
+}
+
+func receiveSseMessage(t *testing.T, received <-chan *types.SseBrokerMessage) *types.SseBrokerMessage {
+	t.Helper()
+
+	select {
+	case message := <-received:
+		return message
+	case <-time.After(2 * time.Second):
+		t.Fatal("발행한 메시지를 받지 못함")
+		return nil
+	}
+}
+
+func TestRedisSseBrokerPublishReachesAllInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	publisher := newTestRedisSseBroker(t, server)
+	other := newTestRedisSseBroker(t, server)
+	fromPublisher := subscribeTestSseBroker(t, ctx, publisher)
+	fromOther := subscribeTestSseBroker(t, ctx, other)
+
+	sent := &types.SseBrokerMessage{
+		ReqId:        "req-1",
+		EventId:      42,
+		Message:      `{"result":"ok"}`,
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+		TraceContext: map[string]string{"traceparent": "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01"},
+	}
+	if err := publisher.Publish(ctx, sent); err != nil {
+		t.Fatalf("발행 실패: %v", err)
+	}
+
+	// 발행한 인스턴스를 포함한 모든 인스턴스가 같은 메시지를 받아야 함
+	for name, received := range map[string]<-chan *types.SseBrokerMessage{"publisher": fromPublisher, "other": fromOther} {
+		message := receiveSseMessage(t, received)
+		if message.ReqId != sent.ReqId || message.EventId != sent.EventId || message.Message != sent.Message ||
+			!message.CreatedAt.Equal(sent.CreatedAt) || message.TraceContext["traceparent"] != sent.TraceContext["traceparent"] {
+			t.Errorf("%s가 받은 메시지가 발행한 메시지와 다름: %+v", name, message)
+		}
+	}
+}
+
+func TestRedisSseBrokerSkipsInvalidPayload(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := newTestRedisSseBroker(t, server)
+	received := subscribeTestSseBroker(t, ctx, broker)
+
+	// 잘못된 메시지는 건너뛰고 이후 메시지는 계속 받아야 함
+	server.Publish(testSseChannel, "not json")
+	if err := broker.Publish(ctx, &types.SseBrokerMessage{ReqId: "req-1", EventId: 1, Message: "a"}); err != nil {
+		t.Fatalf("발행 실패: %v", err)
+	}
+
+	if message := receiveSseMessage(t, received); message.ReqId != "req-1" || message.Message != "a" {
+		t.Errorf("받은 메시지: %+v, 기대값: req-1/a", message)
+	}
+}
+
+func TestRedisSseBrokerUnsubscribesOnContextDone(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	broker := newTestRedisSseBroker(t, server)
+	subscribeTestSseBroker(t, ctx, broker)
+	if subscribers := server.PubSubNumSub(testSseChannel)[testSseChannel]; subscribers != 1 {
+		t.Fatalf("구독자 수: %d, 기대값: 1", subscribers)
+	}
+
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for server.PubSubNumSub(testSseChannel)[testSseChannel] != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("ctx가 끝난 뒤에도 구독이 해지되지 않음")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewRedisSseBrokerRejectsInvalidOptions(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	if _, err := NewRedisSseBroker(nil, testSseChannel); err == nil {
+		t.Error("Redis 클라이언트가 없으면 오류를 반환해야 함")
+	}
+	if _, err := NewRedisSseBroker(redis.NewClient(&redis.Options{Addr: server.Addr()}), ""); err == nil {
+		t.Error("채널 이름이 비어 있으면 오류를 반환해야 함")
+	}
+
+	addr := server.Addr()
+	server.Close()
+	if _, err := NewRedisSseBroker(redis.NewClient(&redis.Options{Addr: addr}), testSseChannel); err == nil {
+		t.Error("Redis에 연결할 수 없으면 오류를 반환해야 함")
+	}
+}
+
+func TestMemorySseBrokerPublishReachesAllSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := NewMemorySseBroker()
+	first := subscribeTestSseBroker(t, ctx, broker)
+	second := subscribeTestSseBroker(t, ctx, broker)
+
+	if err := broker.Publish(ctx, &types.SseBrokerMessage{ReqId: "req-1", EventId: 1, Message: "a"}); err != nil {
+		t.Fatalf("발행 실패: %v", err)
+	}
+
+	// 메모리 통로는 Publish가 반환되기 전에 전달을 마침
+	for _, received := range []<-chan *types.SseBrokerMessage{first, second} {
+		select {
+		case message := <-received:
+			if message.ReqId != "req-1" {
+				t.Errorf("받은 메시지: %+v", message)
+			}
+		default:
+			t.Error("구독자가 메시지를 받지 못함")
+		}
+	}
+}
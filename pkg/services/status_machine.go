@@ -0,0 +1,99 @@
+package services
+
+import (
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// statusTransitions는 허용된 서버 상태 전이입니다 (관리자 지정은 제한 없음)
+// 비정상 서버는 바로 라우팅 대상으로 돌아가지 않고 warning을 거쳐 복귀합니다
+// unknown을 거쳐 warning을 건너뛰지 않도록 비정상 서버는 메트릭이 끊겨도 unknown으로 바꾸지 않습니다
+var statusTransitions = map[types.ServerStatus][]types.ServerStatus{
+	types.StatusUnknown:   {types.StatusExcellent, types.StatusGood, types.StatusWarning, types.StatusUnhealthy},
+	types.StatusExcellent: {types.StatusGood, types.StatusWarning, types.StatusUnhealthy, types.StatusUnknown},
+	types.StatusGood:      {types.StatusExcellent, types.StatusWarning, types.StatusUnhealthy, types.StatusUnknown},
+	types.StatusWarning:   {types.StatusExcellent, types.StatusGood, types.StatusUnhealthy, types.StatusUnknown},
+	types.StatusUnhealthy: {types.StatusWarning},
+}
+
+// statusLevel은 점수 기반 상태의 순서입니다
+var statusLevels = []types.ServerStatus{
+	types.StatusUnhealthy,
+	types.StatusWarning,
+	types.StatusGood,
+	types.StatusExcellent,
+}
+
+// statusThresholds는 각 상태에 들어가기 위한 최소 점수입니다
+var statusThresholds = map[types.ServerStatus]float64{
+	types.StatusUnhealthy: 0,
+	types.StatusWarning:   configs.ScoreWarning,
+	types.StatusGood:      configs.ScoreGood,
+	types.StatusExcellent: configs.ScoreExcellent,
+}
+
+// canTransition 상태 전이 허용 여부
+func canTransition(from types.ServerStatus, to types.ServerStatus) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// constrainTransition 허용되지 않은 전이를 가능한 가장 가까운 상태로 제한
+// 비정상 상태에서 더 좋은 상태로 가는 입력은 warning으로 제한합니다
+func constrainTransition(from types.ServerStatus, to types.ServerStatus) types.ServerStatus {
+	if from == to || canTransition(from, to) {
+		return to
+	}
+	if from == types.StatusUnhealthy && statusLevel(to) > statusLevel(types.StatusUnhealthy) {
+		return types.StatusWarning
+	}
+	return from
+}
+
+func statusLevel(status types.ServerStatus) int {
+	for level, candidate := range statusLevels {
+		if candidate == status {
+			return level
+		}
+	}
+	return -1
+}
+
+// scoreStatus 점수로 다음 상태 결정 (경계 위아래 hysteresis만큼은 현재 상태 유지)
+func scoreStatus(current types.ServerStatus, score float64, hysteresis float64) types.ServerStatus {
+	raw := types.StatusUnhealthy
+	for _, status := range statusLevels {
+		if score >= statusThresholds[status] {
+			raw = status
+		}
+	}
+
+	currentLevel := statusLevel(current)
+	if currentLevel < 0 {
+		// unknown은 점수 그대로 분류
+		return raw
+	}
+
+	rawLevel := statusLevel(raw)
+	switch {
+	case rawLevel > currentLevel:
+		// 올라갈 때는 경계보다 hysteresis만큼 높아야 함
+		for level := rawLevel; level > currentLevel; level-- {
+			if score >= statusThresholds[statusLevels[level]]+hysteresis {
+				return statusLevels[level]
+			}
+		}
+		return current
+	case rawLevel < currentLevel:
+		// 내려갈 때는 현재 상태 경계보다 hysteresis만큼 낮아야 함
+		if score < statusThresholds[current]-hysteresis {
+			return raw
+		}
+		return current
+	}
+	return current
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
+	"github.com/sh5080/ndns-router/pkg/types"
+	"github.com/sh5080/ndns-router/pkg/utils"
+)
+
+// unknownZone은 존 정보가 없는 서버의 카운터 이름입니다
+const unknownZone = "unknown"
+
+// zoneCounter는 존별 요청 카운터입니다
+type zoneCounter struct {
+	requests    uint64
+	spilled     uint64
+	crossRegion uint64
+}
+
+// zoneServiceImpl implements the ZoneService interface
+type zoneServiceImpl struct {
+	serverService interfaces.ServerService
+	zone          string
+	region        string
+	minHealthy    int
+	saturation    float64
+
+	mutex       sync.Mutex
+	counters    map[string]*zoneCounter
+	spillEvents map[types.SpillReason]uint64
+}
+
+// NewZoneService creates a new instance of ZoneService
+func NewZoneService(serverService interfaces.ServerService) (interfaces.ZoneService, error) {
+	if serverService == nil {
+		return nil, errors.New("server service cannot be nil")
+	}
+
+	cfg := configs.GetConfig().Routing
+	if cfg.ZoneSpillSaturation <= 0 || cfg.ZoneSpillSaturation > 1 {
+		return nil, errors.New("ZONE_SPILL_SATURATION은 0보다 크고 1 이하여야 합니다")
+	}
+
+	return &zoneServiceImpl{
+		serverService: serverService,
+		zone:          cfg.Zone,
+		region:        cfg.Region,
+		minHealthy:    cfg.ZoneSpillMinHealthy,
+		saturation:    cfg.ZoneSpillSaturation,
+		counters:      make(map[string]*zoneCounter),
+		spillEvents:   make(map[types.SpillReason]uint64),
+	}, nil
+}
+
+// filterZone은 라우터와 같은 존의 서버만 반환합니다
+func (z *zoneServiceImpl) filterZone(servers []*types.Server) []*types.Server {
+	local := make([]*types.Server, 0, len(servers))
+	for _, server := range servers {
+		if server.Zone == z.zone {
+			local = append(local, server)
+		}
+	}
+	return local
+}
+
+// filterRegion은 라우터와 같은 리전이면서 다른 존인 서버만 반환합니다
+func (z *zoneServiceImpl) filterRegion(servers []*types.Server) []*types.Server {
+	regional := make([]*types.Server, 0, len(servers))
+	for _, server := range servers {
+		if server.Region == z.region && server.Zone != z.zone {
+			regional = append(regional, server)
+		}
+	}
+	return regional
+}
+
+// spill은 같은 리전의 다른 존을 먼저 후보로 두고, 없으면 전체 서버로 넘깁니다
+func (z *zoneServiceImpl) spill(decision *types.ZoneDecision, reason types.SpillReason) *types.ZoneDecision {
+	decision.Spill = reason
+
+	if z.region != "" {
+		regionalExcellent := z.filterRegion(decision.ExcellentServers)
+		regionalGood := z.filterRegion(decision.GoodServers)
+		if len(regionalExcellent)+len(regionalGood) > 0 {
+			decision.ExcellentServers = regionalExcellent
+			decision.GoodServers = regionalGood
+			return decision
+		}
+	}
+
+	decision.CrossRegion = true
+	return decision
+}
+
+// Select 같은 존 우선 후보 선택
+func (z *zoneServiceImpl) Select(group *types.ServerGroup) *types.ZoneDecision {
+	decision := &types.ZoneDecision{
+		ExcellentServers: group.ExcellentServers,
+		GoodServers:      group.GoodServers,
+	}
+
+	// 라우터 존이 설정되지 않으면 존 구분 없이 사용
+	if z.zone == "" {
+		return decision
+	}
+
+	localExcellent := z.filterZone(group.ExcellentServers)
+	localGood := z.filterZone(group.GoodServers)
+	healthy := len(localExcellent) + len(localGood)
+
+	// [1] 같은 존 정상 서버 부족 시 다른 존으로 넘김
+	if healthy == 0 || healthy < z.minHealthy {
+		return z.spill(decision, types.SpillUnhealthy)
+	}
+
+	// [2] 같은 존 서버 포화 시 다른 존으로 넘김
+	active := 0
+	for _, servers := range [][]*types.Server{localExcellent, localGood} {
+		for _, server := range servers {
+			active += z.serverService.GetActiveRequests(server.ServerId)
+		}
+	}
+	usage := float64(active) / float64(healthy*configs.MaxConcurrentRequests)
+	if usage >= z.saturation {
+		return z.spill(decision, types.SpillSaturated)
+	}
+
+	decision.ExcellentServers = localExcellent
+	decision.GoodServers = localGood
+	return decision
+}
+
+// RecordTraffic 존별 요청 수 기록 (선택된 서버가 같은 존이면 넘김으로 세지 않음)
+func (z *zoneServiceImpl) RecordTraffic(server *types.Server, spill types.SpillReason) types.SpillReason {
+	if server == nil {
+		return types.SpillNone
+	}
+
+	// 넘김 판정이 났더라도 실제로 같은 존 서버가 선택되었으면 넘김이 아님
+	if server.Zone == z.zone {
+		spill = types.SpillNone
+	}
+
+	zone := server.Zone
+	if zone == "" {
+		zone = unknownZone
+	}
+
+	z.mutex.Lock()
+	defer z.mutex.Unlock()
+
+	counter, exists := z.counters[zone]
+	if !exists {
+		counter = &zoneCounter{}
+		z.counters[zone] = counter
+	}
+	counter.requests++
+
+	if spill != types.SpillNone {
+		counter.spilled++
+		z.spillEvents[spill]++
+		if z.region != "" && server.Region != z.region {
+			counter.crossRegion++
+			utils.Infof("리전 넘김 발생: %s/%s -> %s/%s (이유: %s)", z.region, z.zone, server.Region, zone, spill)
+		} else {
+			utils.Infof("존 넘김 발생: %s -> %s (이유: %s)", z.zone, zone, spill)
+		}
+	}
+
+	return spill
+}
+
+// GetZoneStats 존별 요청 현황 조회
+func (z *zoneServiceImpl) GetZoneStats() *types.ZoneStats {
+	z.mutex.Lock()
+	defer z.mutex.Unlock()
+
+	stats := &types.ZoneStats{
+		RouterZone:   z.zone,
+		RouterRegion: z.region,
+		Zones:        make([]types.ZoneTraffic, 0, len(z.counters)),
+		SpillEvents:  make(map[types.SpillReason]uint64, len(z.spillEvents)),
+	}
+
+	for zone, counter := range z.counters {
+		stats.Zones = append(stats.Zones, types.ZoneTraffic{
+			Zone:        zone,
+			Local:       z.zone != "" && zone == z.zone,
+			Requests:    counter.requests,
+			Spilled:     counter.spilled,
+			CrossRegion: counter.crossRegion,
+		})
+	}
+	sort.Slice(stats.Zones, func(i, j int) bool {
+		return stats.Zones[i].Zone < stats.Zones[j].Zone
+	})
+
+	for reason, count := range z.spillEvents {
+		stats.SpillEvents[reason] = count
+	}
+
+	return stats
+}
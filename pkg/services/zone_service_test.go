@@ -0,0 +1,157 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// newTestZoneService 라우터 위치가 zone-a/region-1인 존 서비스 생성
+func newTestZoneService(t *testing.T, minHealthy int) (*zoneServiceImpl, *serverServiceImpl) {
+	t.Helper()
+
+	serverService := newTestServerService(t, nil).(*serverServiceImpl)
+	return &zoneServiceImpl{
+		serverService: serverService,
+		zone:          "zone-a",
+		region:        "region-1",
+		minHealthy:    minHealthy,
+		saturation:    0.8,
+		counters:      make(map[string]*zoneCounter),
+		spillEvents:   make(map[types.SpillReason]uint64),
+	}, serverService
+}
+
+// zoneServer 위치가 지정된 서버 생성
+func zoneServer(serverId, zone, region string) *types.Server {
+	return &types.Server{ServerId: serverId, Zone: zone, Region: region}
+}
+
+// serverIds 후보 서버 ID 목록
+func serverIds(servers ...[]*types.Server) []string {
+	ids := []string{}
+	for _, list := range servers {
+		for _, server := range list {
+			ids = append(ids, server.ServerId)
+		}
+	}
+	return ids
+}
+
+// TestZoneSelect 같은 존 우선 선택과 넘김 이유, 리전 우선 넘김 확인
+func TestZoneSelect(t *testing.T) {
+	local := zoneServer("local", "zone-a", "region-1")
+	regional := zoneServer("regional", "zone-b", "region-1")
+	remote := zoneServer("remote", "zone-c", "region-2")
+
+	tests := []struct {
+		name            string
+		excellent       []*types.Server
+		good            []*types.Server
+		minHealthy      int
+		localActive     int // 같은 존 서버의 진행 중 요청 수
+		wantIds         []string
+		wantSpill       types.SpillReason
+		wantCrossRegion bool
+	}{
+		{
+			name:      "같은 존 서버만 후보",
+			excellent: []*types.Server{local, regional},
+			good:      []*types.Server{remote},
+			wantIds:   []string{"local"},
+		},
+		{
+			name:      "같은 존 서버가 없으면 같은 리전으로 넘김",
+			excellent: []*types.Server{regional, remote},
+			wantIds:   []string{"regional"},
+			wantSpill: types.SpillUnhealthy,
+		},
+		{
+			name:            "같은 리전 서버도 없으면 전체로 넘김",
+			good:            []*types.Server{remote},
+			wantIds:         []string{"remote"},
+			wantSpill:       types.SpillUnhealthy,
+			wantCrossRegion: true,
+		},
+		{
+			name:       "같은 존 정상 서버가 최소 개수보다 적으면 넘김",
+			excellent:  []*types.Server{local, regional},
+			minHealthy: 2,
+			wantIds:    []string{"regional"},
+			wantSpill:  types.SpillUnhealthy,
+		},
+		{
+			name:        "같은 존 서버가 포화되면 넘김",
+			excellent:   []*types.Server{local, regional},
+			localActive: configs.MaxConcurrentRequests,
+			wantIds:     []string{"regional"},
+			wantSpill:   types.SpillSaturated,
+		},
+		{
+			name:        "포화 기준 미만이면 같은 존 유지",
+			excellent:   []*types.Server{local, regional},
+			localActive: 1,
+			wantIds:     []string{"local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoneService, serverService := newTestZoneService(t, tt.minHealthy)
+			// 진행 중 요청은 등록된 서버만 추적
+			if err := serverService.AddServer(testServer(local.ServerId, 0)); err != nil {
+				t.Fatalf("서버 추가 실패: %v", err)
+			}
+			for i := 0; i < tt.localActive; i++ {
+				serverService.BeginRequest(local.ServerId)
+			}
+
+			decision := zoneService.Select(&types.ServerGroup{ExcellentServers: tt.excellent, GoodServers: tt.good})
+			ids := serverIds(decision.ExcellentServers, decision.GoodServers)
+			if len(ids) != len(tt.wantIds) {
+				t.Fatalf("후보 %v, 기대 %v", ids, tt.wantIds)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIds[i] {
+					t.Fatalf("후보 %v, 기대 %v", ids, tt.wantIds)
+				}
+			}
+			if decision.Spill != tt.wantSpill || decision.CrossRegion != tt.wantCrossRegion {
+				t.Fatalf("넘김 %q (리전 %v), 기대 %q (리전 %v)", decision.Spill, decision.CrossRegion, tt.wantSpill, tt.wantCrossRegion)
+			}
+		})
+	}
+}
+
+// TestZoneRecordTraffic 실제로 다른 존 서버가 선택된 경우만 넘김으로 세는지 확인
+func TestZoneRecordTraffic(t *testing.T) {
+	zoneService, _ := newTestZoneService(t, 0)
+
+	// 넘김 판정이 났지만 같은 존 서버가 선택됨
+	if spill := zoneService.RecordTraffic(zoneServer("local", "zone-a", "region-1"), types.SpillSaturated); spill != types.SpillNone {
+		t.Fatalf("같은 존 서버 선택은 넘김이 아닙니다: %q", spill)
+	}
+	zoneService.RecordTraffic(zoneServer("regional", "zone-b", "region-1"), types.SpillUnhealthy)
+	zoneService.RecordTraffic(zoneServer("remote", "zone-c", "region-2"), types.SpillSaturated)
+	zoneService.RecordTraffic(zoneServer("nozone", "", ""), types.SpillNone)
+
+	stats := zoneService.GetZoneStats()
+	want := map[string]types.ZoneTraffic{
+		"unknown": {Zone: "unknown", Requests: 1},
+		"zone-a":  {Zone: "zone-a", Local: true, Requests: 1},
+		"zone-b":  {Zone: "zone-b", Requests: 1, Spilled: 1},
+		"zone-c":  {Zone: "zone-c", Requests: 1, Spilled: 1, CrossRegion: 1},
+	}
+	if len(stats.Zones) != len(want) {
+		t.Fatalf("존 통계 %+v", stats.Zones)
+	}
+	for _, traffic := range stats.Zones {
+		if traffic != want[traffic.Zone] {
+			t.Fatalf("존 %s 통계 %+v, 기대 %+v", traffic.Zone, traffic, want[traffic.Zone])
+		}
+	}
+	if stats.SpillEvents[types.SpillUnhealthy] != 1 || stats.SpillEvents[types.SpillSaturated] != 1 {
+		t.Fatalf("넘김 이유별 횟수 %v", stats.SpillEvents)
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// AccessLogRecord는 프록시 요청 하나의 접근 로그입니다
+type AccessLogRecord struct {
+	Time              time.Time `json:"time"`
+	RequestId         string    `json:"requestId"`
+	ClientIp          string    `json:"clientIp"`
+	Method            string    `json:"method"`
+	Path              string    `json:"path"`
+	Status            int       `json:"status"`                      // 클라이언트 응답 상태 코드
+	ServerId          string    `json:"serverId,omitempty"`          // 마지막으로 시도한 서버
+	ServerType        string    `json:"serverType,omitempty"`        // 마지막으로 시도한 서버 종류
+	Spill             string    `json:"spill,omitempty"`             // 다른 존으로 넘긴 이유
+	Attempts          int       `json:"attempts"`                    // 업스트림 시도 횟수 (재시도 포함)
+	Fallback          string    `json:"fallback,omitempty"`          // 서버리스로 넘긴 이유
+	Forced            bool      `json:"forced,omitempty"`            // 서버리스 강제 사용 비율로 서버리스를 사용했는지 (폴백 아님)
+	UpstreamStatus    int       `json:"upstreamStatus,omitempty"`    // 마지막 업스트림 응답 상태 코드
+	UpstreamLatencyMs float64   `json:"upstreamLatencyMs,omitempty"` // 마지막 업스트림 응답 시간
+	LatencyMs         float64   `json:"latencyMs"`                   // 라우터 전체 처리 시간
+	BytesIn           int       `json:"bytesIn"`
+	BytesOut          int       `json:"bytesOut"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// Succeeded는 폴백이나 오류 없이 성공한 요청인지 확인합니다 (샘플링 대상)
+func (r *AccessLogRecord) Succeeded() bool {
+	return r.Status < 400 && r.Fallback == "" && r.Error == ""
+}
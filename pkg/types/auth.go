@@ -0,0 +1,41 @@
+package types
+
+import "path"
+
+// IngestCredential은 수신 경로(/internal, /metrics) 호출자 인증 정보입니다
+// Secret이 있으면 HMAC 서명, Token이 있으면 Bearer 토큰으로 인증합니다
+type IngestCredential struct {
+	Id       string   `json:"id" yaml:"id"`
+	Secret   string   `json:"secret,omitempty" yaml:"secret,omitempty"`     // HMAC 서명 공유 비밀키
+	Token    string   `json:"token,omitempty" yaml:"token,omitempty"`       // Bearer 토큰
+	Servers  []string `json:"servers,omitempty" yaml:"servers,omitempty"`   // 갱신 가능한 서버 ID 패턴 (예: api-*, "*"는 전체)
+	Analysis bool     `json:"analysis,omitempty" yaml:"analysis,omitempty"` // 분석 결과 전달 허용 여부
+}
+
+// AnonymousIngestCredential은 인증을 명시적으로 끈 경우(INGEST_AUTH_DISABLED) 모든 수신 요청의 호출자입니다
+var AnonymousIngestCredential = &IngestCredential{
+	Id:       "anonymous",
+	Servers:  []string{"*"},
+	Analysis: true,
+}
+
+// AllowsServer는 호출자가 해당 서버 정보를 갱신할 수 있는지 확인합니다
+func (c *IngestCredential) AllowsServer(serverId string) bool {
+	for _, pattern := range c.Servers {
+		if matched, err := path.Match(pattern, serverId); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IngestRequest는 인증에 필요한 수신 요청 정보입니다
+type IngestRequest struct {
+	Method        string
+	Uri           string // 쿼리를 포함한 요청 경로
+	Body          []byte
+	Authorization string // Authorization 헤더 (Bearer 토큰)
+	KeyId         string // 서명 키 ID 헤더
+	Timestamp     string // 서명 시각 헤더 (유닉스 초)
+	Signature     string // 서명 헤더 (hex, "sha256=" 접두사 허용)
+}
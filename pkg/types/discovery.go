@@ -0,0 +1,75 @@
+package types
+
+import "time"
+
+// DiscoveredServer는 디스커버리 제공자가 알려주는 서버 정의입니다
+type DiscoveredServer struct {
+	ServerId   string            `json:"serverId" yaml:"serverId"`
+	ServerUrl  string            `json:"serverUrl" yaml:"serverUrl"`
+	ServerType string            `json:"serverType,omitempty" yaml:"serverType,omitempty"`
+	Zone       string            `json:"zone,omitempty" yaml:"zone,omitempty"`
+	Region     string            `json:"region,omitempty" yaml:"region,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// ToServer는 레지스트리에 등록할 서버 정보로 변환합니다
+func (d DiscoveredServer) ToServer() *Server {
+	return &Server{
+		ServerId:   d.ServerId,
+		ServerUrl:  d.ServerUrl,
+		ServerType: d.ServerType,
+		Zone:       d.Zone,
+		Region:     d.Region,
+		Labels:     d.Labels,
+	}
+}
+
+// EndpointState는 디스커버리 제공자가 알려주는 엔드포인트 상태입니다
+type EndpointState string
+
+const (
+	EndpointServing     EndpointState = ""            // 요청 처리 중 (등록)
+	EndpointTerminating EndpointState = "terminating" // 종료 중이지만 요청 처리 가능 (드레인)
+	EndpointStopped     EndpointState = "stopped"     // 요청 처리 불가 (즉시 제거)
+)
+
+// DiscoveryStatus는 디스커버리 제공자의 동기화 현황입니다
+type DiscoveryStatus struct {
+	Provider  string     `json:"provider"`
+	Servers   []string   `json:"servers"`             // 현재 제공자가 관리하는 서버 ID 목록
+	Syncs     uint64     `json:"syncs"`               // 반영한 갱신 횟수
+	LastSync  *time.Time `json:"lastSync,omitempty"`  // 마지막 반영 시간
+	LastError string     `json:"lastError,omitempty"` // 마지막 오류
+}
+
+// DnsRecordType은 DNS 디스커버리에서 조회할 레코드 종류입니다
+type DnsRecordType string
+
+const (
+	DnsRecordA   DnsRecordType = "a"   // A/AAAA 레코드 (포트는 설정값 사용)
+	DnsRecordSrv DnsRecordType = "srv" // SRV 레코드 (포트, 우선순위, 가중치 포함)
+)
+
+// DnsDiscoveryOptions는 DNS 디스커버리 제공자 설정입니다
+type DnsDiscoveryOptions struct {
+	Name     string        // 조회할 호스트 이름
+	Type     DnsRecordType // 레코드 종류
+	Port     int           // A/AAAA 레코드에 사용할 포트
+	Scheme   string        // 서버 URL 스킴 (http, https)
+	Resolver string        // DNS 서버 주소 (host:port)
+	MinTtl   time.Duration // 재조회 최소 간격
+	MaxTtl   time.Duration // 재조회 최대 간격
+	// 발견한 서버에 붙일 기본 라벨 (예: metricsMode=scrape)
+	Labels map[string]string
+}
+
+// KubernetesDiscoveryOptions는 쿠버네티스 EndpointSlice 디스커버리 제공자 설정입니다
+type KubernetesDiscoveryOptions struct {
+	Namespace string // 서비스 네임스페이스
+	Service   string // 감시할 서비스 이름
+	PortName  string // 사용할 포트 이름 (비어 있으면 첫 번째 포트)
+	Scheme    string // 서버 URL 스킴 (http, https)
+	PodLabels bool   // 파드 라벨을 서버 라벨로 복사할지 여부
+	// 발견한 서버에 붙일 기본 라벨 (파드 라벨과 예약 라벨이 우선)
+	Labels map[string]string
+}
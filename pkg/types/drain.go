@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// DrainAction은 드레인 완료 후 서버 처리 방식입니다
+type DrainAction string
+
+const (
+	DrainActionRemove DrainAction = "remove" // 레지스트리에서 제거
+	DrainActionPark   DrainAction = "park"   // 레지스트리에 남기되 라우팅에서 제외
+)
+
+// DrainPhase는 드레인 진행 단계입니다
+type DrainPhase string
+
+const (
+	DrainPhaseDraining DrainPhase = "draining" // 진행 중인 요청 완료 대기
+	DrainPhaseParked   DrainPhase = "parked"   // 드레인 완료, 라우팅 제외 상태
+)
+
+// DrainStatus는 서버 드레인 진행 상황입니다
+type DrainStatus struct {
+	ServerId       string      `json:"serverId"`
+	Phase          DrainPhase  `json:"phase"`
+	Action         DrainAction `json:"action"`
+	StartedAt      time.Time   `json:"startedAt"`
+	Deadline       time.Time   `json:"deadline"`
+	ActiveRequests int         `json:"activeRequests"`        // 남은 진행 중 요청 수
+	CompletedAt    *time.Time  `json:"completedAt,omitempty"` // 드레인 완료 시간
+	Forced         bool        `json:"forced"`                // 타임아웃으로 강제 완료 여부
+}
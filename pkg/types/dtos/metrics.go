@@ -0,0 +1,37 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// MetricsUpdateRequest는 메트릭 업데이트 요청 구조체입니다 (푸시 요청과 JSON 수집 응답에 공통 사용)
+type MetricsUpdateRequest struct {
+	AppName       string            `json:"app_name"`
+	ServerURL     string            `json:"server_url"`
+	ServerType    string            `json:"server_type"`
+	Zone          string            `json:"zone"`
+	Region        string            `json:"region"`
+	Labels        map[string]string `json:"labels"`
+	CPUUsage      float64           `json:"cpu_usage"`     // CPU 사용률 (%, 0-100)
+	MemoryUsage   float64           `json:"memory_usage"`  // 메모리 사용률 (%, 0-100)
+	ErrorRate     float64           `json:"error_rate"`    // 에러율 (%, 0-100)
+	ResponseTime  float64           `json:"response_time"` // 응답 시간 (ms)
+	TotalRequests int64             `json:"total_requests"`
+	ErrorRequests int64             `json:"error_requests"`
+	Timestamp     time.Time         `json:"timestamp"`
+}
+
+// Report는 요청의 메트릭 값을 레지스트리에 병합할 보고 형식으로 변환합니다
+func (r *MetricsUpdateRequest) Report() *types.MetricsReport {
+	return &types.MetricsReport{
+		CPUUsage:      r.CPUUsage,
+		MemoryUsage:   r.MemoryUsage,
+		ErrorRate:     r.ErrorRate,
+		Latency:       r.ResponseTime,
+		TotalRequests: r.TotalRequests,
+		ErrorRequests: r.ErrorRequests,
+		Timestamp:     r.Timestamp,
+	}
+}
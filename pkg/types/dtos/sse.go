@@ -10,9 +10,13 @@ type MessageRequest struct {
 type ActiveConnections struct {
 	TotalConnections int                `json:"totalConnections"`
 	Connections      []types.Connection `json:"connections"`
+	PendingMailboxes int                `json:"pendingMailboxes"` // 구독자 연결 전 메시지를 보관 중인 reqId 수
+	MailboxEvictions uint64             `json:"mailboxEvictions"` // 만료되거나 넘쳐서 버린 연결 전 메시지 수
+	Reaped           map[string]uint64  `json:"reaped"`           // 최대 연결 시간(expired), 유휴 시간(idle)을 넘겨 정리한 연결 수
 }
 
 type SsePayload struct {
+	Id   uint64         `json:"-"` // SSE id 필드 (0이면 보내지 않음)
 	Type SseMessageType `json:"type"`
 	Data interface{}    `json:"data,omitempty"`
 }
@@ -23,4 +27,6 @@ const (
 	SseConnect   SseMessageType = "connect"
 	SseMessage   SseMessageType = "message"
 	SseHeartbeat SseMessageType = "heartbeat"
+	SseClose     SseMessageType = "close"    // 라우터가 연결을 끝내기 전 마지막 이벤트 (reason 포함)
+	SseRegistry  SseMessageType = "registry" // 서버 레지스트리 이벤트 (관리자 스트림)
 )
@@ -0,0 +1,22 @@
+package types
+
+import "errors"
+
+var (
+	// ErrServerNotFound는 레지스트리에 서버가 없을 때 반환됩니다
+	ErrServerNotFound = errors.New("server not found")
+	// ErrServerDraining은 드레인 중이거나 주차된 서버를 갱신하려 할 때 반환됩니다
+	ErrServerDraining = errors.New("server is draining")
+	// ErrNotDraining은 드레인 중이 아닌 서버의 드레인을 취소하려 할 때 반환됩니다
+	ErrNotDraining = errors.New("server is not draining")
+	// ErrNoStatusOverride는 관리자 지정 상태가 없는 서버의 지정을 해제하려 할 때 반환됩니다
+	ErrNoStatusOverride = errors.New("server has no status override")
+	// ErrMissingCredential은 수신 요청에 토큰이나 서명이 없을 때 반환됩니다
+	ErrMissingCredential = errors.New("missing credential")
+	// ErrInvalidCredential은 알 수 없는 토큰, 키 ID 또는 잘못된 서명일 때 반환됩니다
+	ErrInvalidCredential = errors.New("invalid credential")
+	// ErrSignatureExpired는 서명 시각이 허용 구간을 벗어났을 때 반환됩니다
+	ErrSignatureExpired = errors.New("signature timestamp outside replay window")
+	// ErrSignatureReplayed는 이미 사용된 서명이 다시 들어왔을 때 반환됩니다
+	ErrSignatureReplayed = errors.New("signature already used")
+)
@@ -0,0 +1,70 @@
+package types
+
+import "time"
+
+// RegistryEventType은 서버 레지스트리 이벤트 종류입니다
+type RegistryEventType string
+
+const (
+	EventServerAdded   RegistryEventType = "added"          // 서버 추가
+	EventServerRemoved RegistryEventType = "removed"        // 서버 제거
+	EventStatusChanged RegistryEventType = "status_changed" // 서버 상태 변경
+	EventScoreChanged  RegistryEventType = "score_changed"  // 서버 점수 변경
+	EventLabelsChanged RegistryEventType = "labels_changed" // 서버 라벨 변경
+	EventServerDrained RegistryEventType = "drained"        // 드레인 완료
+)
+
+// RegistryEvent는 레지스트리에서 발생한 변경 사항입니다
+type RegistryEvent struct {
+	Type           RegistryEventType `json:"type"`
+	ServerId       string            `json:"serverId"`
+	Time           time.Time         `json:"time"`
+	Server         *Server           `json:"server,omitempty"`         // 변경 후 서버 정보 (제거 시 마지막 정보)
+	PreviousStatus string            `json:"previousStatus,omitempty"` // 상태 변경 전 값
+	PreviousScore  *float64          `json:"previousScore,omitempty"`  // 점수 변경 전 값
+	Reason         string            `json:"reason,omitempty"`
+}
+
+// EventFilter는 구독할 이벤트 조건입니다 (비어 있는 조건은 모두 허용)
+type EventFilter struct {
+	Types     []RegistryEventType `json:"types,omitempty"`
+	ServerIds []string            `json:"serverIds,omitempty"`
+	Selector  *LabelSelector      `json:"selector,omitempty"`
+}
+
+// Matches는 이벤트가 필터 조건을 만족하는지 확인합니다
+func (f EventFilter) Matches(event RegistryEvent) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, eventType := range f.Types {
+			if eventType == event.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.ServerIds) > 0 {
+		matched := false
+		for _, serverId := range f.ServerIds {
+			if serverId == event.ServerId {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if !f.Selector.Empty() {
+		if event.Server == nil || !f.Selector.MatchesServer(event.Server) {
+			return false
+		}
+	}
+
+	return true
+}
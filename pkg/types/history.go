@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+// HistoryTier는 메트릭 이력 보관 단계입니다 (해상도 단위로 묶어 보관 기간만큼 유지)
+type HistoryTier struct {
+	Resolution time.Duration `json:"resolution"`
+	Retention  time.Duration `json:"retention"`
+}
+
+// HistoryQuery는 메트릭 이력 조회 조건입니다
+type HistoryQuery struct {
+	From time.Time
+	To   time.Time
+	Step time.Duration // 0이면 보관 단계의 해상도 사용
+}
+
+// MetricsPoint는 한 구간의 메트릭 이력입니다
+// 서버가 보낸 메트릭은 구간 평균, 라우터가 관측한 프록시 요청은 구간 합계입니다
+type MetricsPoint struct {
+	Time           time.Time `json:"time"`
+	Samples        int       `json:"samples"` // 구간 내 메트릭 푸시 횟수
+	CPUUsage       float64   `json:"cpuUsage"`
+	MemoryUsage    float64   `json:"memoryUsage"`
+	RequestRate    float64   `json:"requestRate"`
+	ErrorRate      float64   `json:"errorRate"`
+	Latency        float64   `json:"responseTime"`
+	Score          float64   `json:"score"`
+	ProxyRequests  int64     `json:"proxyRequests"`  // 라우터가 프록시한 요청 수
+	ProxyErrors    int64     `json:"proxyErrors"`    // 프록시 실패 수
+	ProxyLatencyMs float64   `json:"proxyLatencyMs"` // 프록시 평균 응답 시간 (ms)
+}
@@ -0,0 +1,99 @@
+package types
+
+import "strings"
+
+// 셀렉터에서 서버 필드를 가리키는 예약 라벨 키
+const (
+	LabelServerId   = "serverId"
+	LabelServerType = "serverType"
+	LabelZone       = "zone"
+	LabelRegion     = "region"
+)
+
+// SelectorOperator는 라벨 셀렉터 연산자입니다
+type SelectorOperator string
+
+const (
+	SelectorEquals    SelectorOperator = "="       // key=value
+	SelectorNotEquals SelectorOperator = "!="      // key!=value (키가 없어도 일치)
+	SelectorExists    SelectorOperator = "exists"  // key
+	SelectorNotExists SelectorOperator = "!exists" // !key
+)
+
+// LabelRequirement는 셀렉터의 조건 하나입니다
+type LabelRequirement struct {
+	Key      string           `json:"key"`
+	Operator SelectorOperator `json:"operator"`
+	Value    string           `json:"value,omitempty"`
+}
+
+// Matches는 라벨이 조건을 만족하는지 확인합니다
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+	switch r.Operator {
+	case SelectorEquals:
+		return exists && value == r.Value
+	case SelectorNotEquals:
+		return !exists || value != r.Value
+	case SelectorExists:
+		return exists
+	case SelectorNotExists:
+		return !exists
+	}
+	return false
+}
+
+// String은 조건을 셀렉터 문자열로 반환합니다
+func (r LabelRequirement) String() string {
+	switch r.Operator {
+	case SelectorExists:
+		return r.Key
+	case SelectorNotExists:
+		return "!" + r.Key
+	}
+	return r.Key + string(r.Operator) + r.Value
+}
+
+// LabelSelector는 쉼표로 구분된 조건 목록이며 모든 조건을 만족해야 일치합니다
+// 예: "provider=cloudrun,capacity!=small"
+type LabelSelector struct {
+	Requirements []LabelRequirement `json:"requirements"`
+}
+
+// Empty는 조건이 없는 셀렉터인지 확인합니다 (모든 서버와 일치)
+func (s *LabelSelector) Empty() bool {
+	return s == nil || len(s.Requirements) == 0
+}
+
+// Matches는 라벨이 모든 조건을 만족하는지 확인합니다
+func (s *LabelSelector) Matches(labels map[string]string) bool {
+	if s.Empty() {
+		return true
+	}
+	for _, requirement := range s.Requirements {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesServer는 서버 라벨과 예약 라벨(serverId, serverType, zone, region)로 일치 여부를 확인합니다
+func (s *LabelSelector) MatchesServer(server *Server) bool {
+	if s.Empty() {
+		return true
+	}
+	return s.Matches(server.SelectorLabels())
+}
+
+// String은 셀렉터를 문자열로 반환합니다
+func (s *LabelSelector) String() string {
+	if s.Empty() {
+		return ""
+	}
+	parts := make([]string, 0, len(s.Requirements))
+	for _, requirement := range s.Requirements {
+		parts = append(parts, requirement.String())
+	}
+	return strings.Join(parts, ",")
+}
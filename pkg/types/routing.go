@@ -0,0 +1,9 @@
+package types
+
+// RoutingPolicy는 limit 구간별 서버 선택 우선순위입니다
+// 각 목록은 앞쪽 셀렉터와 일치하는 서버를 먼저 선택합니다
+type RoutingPolicy struct {
+	PriorityLimit2  []*LabelSelector // limit 2 이하
+	PriorityLimit10 []*LabelSelector // limit 10 이하
+	PriorityDefault []*LabelSelector // 그 외
+}
@@ -0,0 +1,56 @@
+package types
+
+// ScoreMetric는 점수 계산에 사용되는 지표 이름입니다
+type ScoreMetric string
+
+const (
+	ScoreMetricCPU       ScoreMetric = "cpuUsage"     // CPU 사용률
+	ScoreMetricMemory    ScoreMetric = "memoryUsage"  // 메모리 사용률
+	ScoreMetricErrorRate ScoreMetric = "errorRate"    // 에러율
+	ScoreMetricLatency   ScoreMetric = "responseTime" // 응답 시간
+)
+
+// ScorePenaltyAction은 감점 규칙이 적용될 때의 동작입니다
+type ScorePenaltyAction string
+
+const (
+	PenaltyCap   ScorePenaltyAction = "cap"   // 점수 상한 적용
+	PenaltyMinus ScorePenaltyAction = "minus" // 점수 차감
+)
+
+// ScorePenaltyRule은 지표가 임계값을 넘을 때 적용되는 감점 규칙입니다
+type ScorePenaltyRule struct {
+	Metric    ScoreMetric        `json:"metric"`
+	Threshold float64            `json:"threshold"`
+	Action    ScorePenaltyAction `json:"action"`
+	Value     float64            `json:"value"`
+}
+
+// ScoreComponent는 지표 하나가 점수에 기여한 내역입니다
+type ScoreComponent struct {
+	Metric       ScoreMetric `json:"metric"`
+	Value        float64     `json:"value"`        // 원본 지표 값
+	Min          float64     `json:"min"`          // 정규화 최소값
+	Max          float64     `json:"max"`          // 정규화 최대값
+	Normalized   float64     `json:"normalized"`   // 정규화 결과 (0-1, 높을수록 좋음)
+	Weight       float64     `json:"weight"`       // 가중치
+	Contribution float64     `json:"contribution"` // 최종 점수 기여분
+}
+
+// AppliedPenalty는 실제로 적용된 감점 내역입니다
+type AppliedPenalty struct {
+	Rule   ScorePenaltyRule `json:"rule"`
+	Before float64          `json:"before"`
+	After  float64          `json:"after"`
+}
+
+// ScoreBreakdown은 서버 점수가 어떻게 계산되었는지 나타냅니다
+type ScoreBreakdown struct {
+	ServerId      string           `json:"serverId,omitempty"`
+	Components    []ScoreComponent `json:"components"`
+	ComputedScore float64          `json:"computedScore"`         // 원본 지표로 계산한 점수
+	PushedScore   *float64         `json:"pushedScore,omitempty"` // 서버가 보낸 점수
+	PushedWeight  float64          `json:"pushedWeight"`          // 푸시 점수 반영 비율
+	Penalties     []AppliedPenalty `json:"penalties"`
+	Score         float64          `json:"score"` // 최종 점수 (0-100)
+}
@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// ScrapeFormat은 메트릭 수집 응답 형식입니다
+type ScrapeFormat string
+
+const (
+	ScrapeFormatAuto       ScrapeFormat = "auto"       // Content-Type으로 판단 (JSON이 아니면 Prometheus 텍스트)
+	ScrapeFormatJson       ScrapeFormat = "json"       // MetricsUpdateRequest 형식 JSON
+	ScrapeFormatPrometheus ScrapeFormat = "prometheus" // Prometheus 텍스트 형식
+)
+
+// ScrapeMetricNames는 Prometheus 텍스트 형식에서 읽을 메트릭 이름입니다
+type ScrapeMetricNames struct {
+	CPUUsage      string
+	MemoryUsage   string
+	ErrorRate     string
+	Latency       string // _seconds로 끝나면 ms로 변환
+	TotalRequests string
+	ErrorRequests string
+}
+
+// ScrapeStatus는 서버별 메트릭 수집 현황입니다
+type ScrapeStatus struct {
+	ServerId    string       `json:"serverId"`
+	Url         string       `json:"url"`
+	Format      ScrapeFormat `json:"format,omitempty"`      // 마지막으로 성공한 응답 형식
+	Failures    int          `json:"failures"`              // 연속 실패 수
+	Stale       bool         `json:"stale"`                 // 수집 실패로 unknown 처리되었는지 여부
+	LastScrape  *time.Time   `json:"lastScrape,omitempty"`  // 마지막 수집 시도 시간
+	LastSuccess *time.Time   `json:"lastSuccess,omitempty"` // 마지막 수집 성공 시간
+	LastError   string       `json:"lastError,omitempty"`
+}
@@ -24,37 +24,78 @@ type OptimalServer struct {
 
 // Server는 서버 정보를 나타내는 구조체입니다
 type Server struct {
-	ServerId      string    `json:"serverId"`
-	ServerUrl     string    `json:"serverUrl"`
-	ServerType    string    `json:"serverType"`
-	CurrentStatus string    `json:"status"`
-	LastUpdated   time.Time `json:"lastUpdated"`
-	Metrics       *Metrics  `json:"metrics,omitempty"`
+	ServerId      string            `json:"serverId"`
+	ServerUrl     string            `json:"serverUrl"`
+	ServerType    string            `json:"serverType"`
+	Zone          string            `json:"zone,omitempty"`   // 가용 영역 (예: ap-northeast-2a, home)
+	Region        string            `json:"region,omitempty"` // 리전 (예: ap-northeast-2)
+	Labels        map[string]string `json:"labels,omitempty"` // 임의 라벨 (예: provider=ec2, capacity=large)
+	CurrentStatus string            `json:"status"`
+	LastUpdated   time.Time         `json:"lastUpdated"`
+	Metrics       *Metrics          `json:"metrics,omitempty"`
+	// 디스커버리 제공자가 알려준 엔드포인트 상태 (레지스트리에는 저장하지 않음)
+	Endpoint EndpointState `json:"-"`
+}
+
+// SelectorLabels는 라벨 셀렉터 평가에 사용할 라벨을 반환합니다
+// 서버 라벨에 serverId, serverType, zone, region 예약 라벨을 더합니다
+func (s *Server) SelectorLabels() map[string]string {
+	labels := make(map[string]string, len(s.Labels)+4)
+	for key, value := range s.Labels {
+		labels[key] = value
+	}
+
+	labels[LabelServerId] = s.ServerId
+	if s.ServerType != "" {
+		labels[LabelServerType] = s.ServerType
+	}
+	if s.Zone != "" {
+		labels[LabelZone] = s.Zone
+	}
+	if s.Region != "" {
+		labels[LabelRegion] = s.Region
+	}
+	return labels
 }
 
 // Metrics represents server metrics
 type Metrics struct {
-	CPUUsage    float64   `json:"cpuUsage"`     // CPU 사용량 (0-1)
-	MemoryUsage float64   `json:"memoryUsage"`  // 메모리 사용량 (bytes)
-	RequestRate float64   `json:"requestRate"`  // 초당 요청 수
-	ErrorRate   float64   `json:"errorRate"`    // 에러율 (0-1)
-	Latency     float64   `json:"responseTime"` // 응답 시간 (초)
-	Score       float64   `json:"score"`        // 서버 점수 (0-100)
-	Timestamp   time.Time `json:"timestamp"`    // 메트릭 수집 시간
+	CPUUsage    float64   `json:"cpuUsage"`              // CPU 사용률 (%, 0-100)
+	MemoryUsage float64   `json:"memoryUsage"`           // 메모리 사용률 (%, 0-100)
+	RequestRate float64   `json:"requestRate"`           // 초당 요청 수
+	ErrorRate   float64   `json:"errorRate"`             // 에러율 (%, 0-100)
+	Latency     float64   `json:"responseTime"`          // 응답 시간 (ms)
+	Score       float64   `json:"score"`                 // 서버 점수 (0-100)
+	PushedScore *float64  `json:"pushedScore,omitempty"` // 서버가 직접 보낸 점수 (선택)
+	Timestamp   time.Time `json:"timestamp"`             // 메트릭 수집 시간
+}
+
+// MetricsReport는 서버가 푸시한 메트릭입니다 (요청 수는 서버 시작 이후 누적 카운터)
+type MetricsReport struct {
+	CPUUsage      float64 // CPU 사용률 (%)
+	MemoryUsage   float64 // 메모리 사용률 (%)
+	ErrorRate     float64 // 카운터로 계산할 수 없을 때 사용하는 에러율 (%)
+	Latency       float64 // 응답 시간 (ms)
+	TotalRequests int64
+	ErrorRequests int64
+	Timestamp     time.Time // 비어 있으면 수신 시간 사용
 }
 
 // OptimalServerRequest는 최적 서버 등록 요청 구조체입니다
 type OptimalServerRequest struct {
 	Servers []struct {
-		ServerId   string `json:"serverId"`
-		ServerUrl  string `json:"serverUrl"`
-		ServerType string `json:"serverType"`
+		ServerId   string            `json:"serverId"`
+		ServerUrl  string            `json:"serverUrl"`
+		ServerType string            `json:"serverType"`
+		Zone       string            `json:"zone"`
+		Region     string            `json:"region"`
+		Labels     map[string]string `json:"labels"`
 		Metrics    struct {
-			CpuUsage     float64 `json:"cpuUsage"`
-			MemoryUsage  float64 `json:"memoryUsage"`
-			ErrorRate    float64 `json:"errorRate"`
-			ResponseTime float64 `json:"responseTime"`
-			Score        float64 `json:"score"`
+			CpuUsage     float64  `json:"cpuUsage"`
+			MemoryUsage  float64  `json:"memoryUsage"`
+			ErrorRate    float64  `json:"errorRate"`
+			ResponseTime float64  `json:"responseTime"`
+			Score        *float64 `json:"score,omitempty"` // 선택 값, 라우터가 원본 지표로 점수를 계산합니다
 		} `json:"metrics"`
 	} `json:"servers"`
 }
@@ -81,18 +122,40 @@ func IsJwtEligible(path string) bool {
 	return JwtEligiblePathsMap[JwtEligiblePaths(path)]
 }
 
-// ConnectionInfo는 SSE 연결 정보를 저장합니다
+// SseEvent는 reqId로 전달하는 SSE 이벤트입니다 (Id는 reqId마다 증가)
+type SseEvent struct {
+	Id        uint64
+	Message   string
+	CreatedAt time.Time
+}
+
+// SseBrokerMessage는 라우터 인스턴스 사이에 발행하는 SSE 메시지입니다
+// EventId는 발행한 인스턴스의 시각 기반 제안값이며, 받은 인스턴스가 reqId의 이전 ID보다 작으면 다음 ID로 올려 붙이므로
+// 같은 메시지라도 인스턴스마다 ID가 다를 수 있습니다 (Last-Event-ID는 같은 인스턴스로 재연결할 때만 이어받음)
+type SseBrokerMessage struct {
+	ReqId        string            `json:"reqId"`
+	EventId      uint64            `json:"eventId"`
+	Message      string            `json:"message"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	TraceContext map[string]string `json:"traceContext,omitempty"` // 발행한 스팬의 traceparent
+}
+
+// ConnectionInfo는 SSE 연결 정보를 저장합니다 (같은 reqId에 구독자마다 하나씩)
 type ConnectionInfo struct {
-	Channel     chan string
-	ConnectedAt time.Time
-	LastActive  time.Time
-	ExpiresAt   time.Time
-	Done        chan struct{}
+	SubscriberId string
+	Channel      chan SseEvent
+	ConnectedAt  time.Time
+	LastActive   time.Time
+	ExpiresAt    time.Time
+	Done         chan struct{}
+	CloseReason  string // 라우터가 연결을 끝낸 사유 (채널을 닫기 전에 기록, 클라이언트가 끊은 경우 비어 있음)
 }
 
 type Connection struct {
 	ReqId             string
+	SubscriberId      string
 	ConnectedAt       time.Time
 	ConnectedDuration time.Duration
+	LastActive        time.Time
 	ExpiresIn         time.Duration
 }
@@ -0,0 +1,53 @@
+package types
+
+import "time"
+
+// StatusTrigger는 서버 상태 전이를 일으킨 입력 종류입니다
+type StatusTrigger string
+
+const (
+	StatusTriggerScore  StatusTrigger = "score"         // 메트릭 푸시로 계산된 점수
+	StatusTriggerProbe  StatusTrigger = "health_probe"  // 헬스 체크 결과
+	StatusTriggerProxy  StatusTrigger = "proxy_failure" // 프록시 요청 실패
+	StatusTriggerStale  StatusTrigger = "stale"         // 메트릭 갱신 중단
+	StatusTriggerAdmin  StatusTrigger = "admin"         // 관리자 지정
+	StatusTriggerPolicy StatusTrigger = "policy"        // 라우팅 정책 (ROUTING_FORCE_GOOD_SELECTOR)
+)
+
+// StatusTransition은 서버 상태 전이 기록입니다
+type StatusTransition struct {
+	From    ServerStatus  `json:"from"`
+	To      ServerStatus  `json:"to"`
+	Trigger StatusTrigger `json:"trigger"`
+	Reason  string        `json:"reason,omitempty"`
+	Time    time.Time     `json:"time"`
+}
+
+// StatusOverride는 관리자가 지정한 서버 상태입니다 (해제 전까지 다른 입력으로 바뀌지 않음)
+type StatusOverride struct {
+	Status    ServerStatus `json:"status"`
+	Reason    string       `json:"reason,omitempty"`
+	SetAt     time.Time    `json:"setAt"`
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"` // 비어 있으면 직접 해제할 때까지 유지
+}
+
+// ServerStatusInfo는 서버 상태 머신의 현재 상태와 전이 기록입니다
+type ServerStatusInfo struct {
+	ServerId       string             `json:"serverId"`
+	Status         ServerStatus       `json:"status"`
+	Override       *StatusOverride    `json:"override,omitempty"`
+	ProbeFailures  int                `json:"probeFailures"`         // 연속 헬스 체크 실패 수
+	ProbeSuccesses int                `json:"probeSuccesses"`        // 연속 헬스 체크 성공 수
+	ProxyFailures  int                `json:"proxyFailures"`         // 연속 프록시 실패 수
+	LastMetrics    *time.Time         `json:"lastMetrics,omitempty"` // 마지막 메트릭 수신 시간
+	Transitions    []StatusTransition `json:"transitions"`           // 오래된 순
+}
+
+// IsValidServerStatus는 정의된 서버 상태인지 확인합니다
+func IsValidServerStatus(status ServerStatus) bool {
+	switch status {
+	case StatusExcellent, StatusGood, StatusWarning, StatusUnhealthy, StatusUnknown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package types
+
+// SpillReason은 다른 존으로 요청을 넘긴 이유입니다
+type SpillReason string
+
+const (
+	SpillNone      SpillReason = ""          // 같은 존에서 처리
+	SpillUnhealthy SpillReason = "unhealthy" // 같은 존 정상 서버 부족
+	SpillSaturated SpillReason = "saturated" // 같은 존 서버 포화
+)
+
+// ZoneDecision은 존 우선 선택 결과입니다
+type ZoneDecision struct {
+	ExcellentServers []*Server   // 후보 최상위 서버 목록
+	GoodServers      []*Server   // 후보 양호 서버 목록
+	Spill            SpillReason // 다른 존으로 넘긴 이유 (없으면 빈 값)
+	CrossRegion      bool        // 같은 리전에 후보가 없어 다른 리전까지 넘겼는지 여부
+}
+
+// ZoneTraffic은 존별 요청 카운터입니다
+type ZoneTraffic struct {
+	Zone        string `json:"zone"`
+	Local       bool   `json:"local"`       // 라우터와 같은 존인지 여부
+	Requests    uint64 `json:"requests"`    // 해당 존으로 보낸 요청 수
+	Spilled     uint64 `json:"spilled"`     // 그 중 존 넘김으로 보낸 요청 수
+	CrossRegion uint64 `json:"crossRegion"` // 그 중 다른 리전으로 넘긴 요청 수
+}
+
+// ZoneStats는 존 우선 라우팅 현황입니다
+type ZoneStats struct {
+	RouterZone   string                 `json:"routerZone"`
+	RouterRegion string                 `json:"routerRegion"`
+	Zones        []ZoneTraffic          `json:"zones"`
+	SpillEvents  map[SpillReason]uint64 `json:"spillEvents"` // 이유별 존 넘김 횟수
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// IngestCredential 인증된 수신 요청 호출자 정보 (인증 미들웨어를 거치지 않았으면 nil)
+func IngestCredential(ctx *fiber.Ctx) *types.IngestCredential {
+	credential, _ := ctx.Locals(configs.IngestCredentialLocalKey).(*types.IngestCredential)
+	return credential
+}
+
+// AllowsServer 호출자가 서버 정보를 갱신할 수 있는지 확인 (인증되지 않은 요청은 거부)
+func AllowsServer(ctx *fiber.Ctx, serverId string) bool {
+	credential := IngestCredential(ctx)
+	return credential != nil && credential.AllowsServer(serverId)
+}
+
+// AllowsAnalysis 호출자가 분석 결과를 전달할 수 있는지 확인 (인증되지 않은 요청은 거부)
+func AllowsAnalysis(ctx *fiber.Ctx) bool {
+	credential := IngestCredential(ctx)
+	return credential != nil && credential.Analysis
+}
+
+// IngestCallerId 로그용 호출자 ID (인증되지 않은 요청은 "-")
+func IngestCallerId(ctx *fiber.Ctx) string {
+	if credential := IngestCredential(ctx); credential != nil {
+		return credential.Id
+	}
+	return "-"
+}
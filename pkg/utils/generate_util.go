@@ -1,9 +1,17 @@
 package utils
 
 import (
+	"regexp"
+	"strings"
 	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/sh5080/ndns-router/pkg/configs"
 )
 
+// 요청 ID에 허용하는 문자 (영문, 숫자, '.', '_', ':', '-')
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
 // Generate 요청 id 생성 및 라운드 로빈 인덱스 반환
 type Generate struct {
 	requestCounter uint64
@@ -18,6 +26,31 @@ func NewGenerate() *Generate {
 	}
 }
 
+// GenerateRequestId 새 요청 id 생성
+func (g *Generate) GenerateRequestId() string {
+	atomic.AddUint64(&g.requestCounter, 1)
+	return uuid.New().String()
+}
+
+// IsValidRequestId 외부에서 받은 요청 id의 형식과 길이 검증
+func IsValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > configs.MaxRequestIdLength {
+		return false
+	}
+	return requestIdPattern.MatchString(requestId)
+}
+
+// ResolveRequestId 유효한 요청 id가 있으면 그대로 사용하고, 없으면 새로 생성
+// 두 번째 반환값은 전달받은 id를 사용했는지 여부입니다
+// 외부에서 정할 수 있는 값이므로 로그·추적용으로만 쓰고 SSE 스트림 식별자로는 쓰지 않습니다
+func ResolveRequestId(incoming string) (string, bool) {
+	if IsValidRequestId(incoming) {
+		// 헤더 값은 요청 버퍼를 참조할 수 있으므로 복사본 사용
+		return strings.Clone(incoming), true
+	}
+	return uuid.New().String(), false
+}
+
 // 다음 라운드 로빈 인덱스 반환
 func (g *Generate) NextRoundRobinIndex(length int) int {
 	if length <= 0 {
@@ -7,18 +7,23 @@ import (
 	"github.com/sh5080/ndns-router/pkg/configs"
 )
 
-var jwtSecret = []byte(configs.GetConfig().App.JwtSecret)
+// jwtSecret 서명 키 (패키지 초기화 시점이 아닌 사용 시점에 설정을 읽음)
+func jwtSecret() []byte {
+	return []byte(configs.GetConfig().App.JwtSecret)
+}
 
 // JwtClaims 구조체
 type SseClaims struct {
-	ReqId string `json:"reqId"`
+	ReqId     string `json:"reqId"`
+	RequestId string `json:"requestId,omitempty"` // 토큰을 발급한 검색 요청의 요청 ID (로그 연결용)
 	jwt.RegisteredClaims
 }
 
 // Jwt 생성 함수
-func GenerateSseToken(reqId string, ttlMinutes int) (string, error) {
+func GenerateSseToken(reqId string, requestId string, ttlMinutes int) (string, error) {
 	claims := SseClaims{
-		ReqId: reqId,
+		ReqId:     reqId,
+		RequestId: requestId,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * time.Duration(ttlMinutes))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -26,13 +31,13 @@ func GenerateSseToken(reqId string, ttlMinutes int) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(jwtSecret())
 }
 
 // Jwt 검증 함수
 func ParseAndValidateSseToken(tokenStr string) (*SseClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &SseClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
+		return jwtSecret(), nil
 	})
 
 	if err != nil || !token.Valid {
@@ -0,0 +1,18 @@
+package utils
+
+import "testing"
+
+func TestSseTokenCarriesIssuerRequestId(t *testing.T) {
+	token, err := GenerateSseToken("stream-1", "search-req-1", 10)
+	if err != nil {
+		t.Fatalf("SSE 토큰 생성 실패: %v", err)
+	}
+
+	claims, err := ParseAndValidateSseToken(token)
+	if err != nil || claims == nil {
+		t.Fatalf("SSE 토큰 검증 실패: %v", err)
+	}
+	if claims.ReqId != "stream-1" || claims.RequestId != "search-req-1" {
+		t.Errorf("토큰 클레임: reqId=%q requestId=%q, 기대값: stream-1/search-req-1", claims.ReqId, claims.RequestId)
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+// 라벨 키/값 형식 (키: 영문, 숫자, '.', '_', '-', '/' / 값: 영문, 숫자, '.', '_', '-')
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,61}[A-Za-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+// ValidateLabels 라벨 키와 값 형식 검증
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("잘못된 라벨 키: %q", key)
+		}
+		if !labelValuePattern.MatchString(value) {
+			return fmt.Errorf("잘못된 라벨 값: %s=%q", key, value)
+		}
+	}
+	return nil
+}
+
+// ParseLabels "key=value" 목록을 라벨로 파싱하고 형식 검증
+func ParseLabels(raw []string) (map[string]string, error) {
+	labels := make(map[string]string, len(raw))
+	for _, part := range raw {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("라벨은 key=value 형식이어야 합니다: %q", part)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels, ValidateLabels(labels)
+}
+
+// ParseLabelSelector "provider=cloudrun,capacity!=small" 형식의 셀렉터 파싱
+// 지원 연산자: key=value, key==value, key!=value, key (존재), !key (미존재)
+func ParseLabelSelector(raw string) (*types.LabelSelector, error) {
+	selector := &types.LabelSelector{
+		Requirements: make([]types.LabelRequirement, 0),
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var requirement types.LabelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			requirement = types.LabelRequirement{Key: key, Operator: types.SelectorNotEquals, Value: value}
+		case strings.Contains(part, "=="):
+			key, value, _ := strings.Cut(part, "==")
+			requirement = types.LabelRequirement{Key: key, Operator: types.SelectorEquals, Value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			requirement = types.LabelRequirement{Key: key, Operator: types.SelectorEquals, Value: value}
+		case strings.HasPrefix(part, "!"):
+			requirement = types.LabelRequirement{Key: part[1:], Operator: types.SelectorNotExists}
+		default:
+			requirement = types.LabelRequirement{Key: part, Operator: types.SelectorExists}
+		}
+
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+		if !labelKeyPattern.MatchString(requirement.Key) {
+			return nil, fmt.Errorf("잘못된 셀렉터 키: %q", part)
+		}
+		if !labelValuePattern.MatchString(requirement.Value) {
+			return nil, fmt.Errorf("잘못된 셀렉터 값: %q", part)
+		}
+
+		selector.Requirements = append(selector.Requirements, requirement)
+	}
+
+	return selector, nil
+}
+
+// ParseLabelSelectors ';'로 구분된 셀렉터 목록 파싱 (우선순위 목록 등에 사용)
+func ParseLabelSelectors(raw []string) ([]*types.LabelSelector, error) {
+	selectors := make([]*types.LabelSelector, 0, len(raw))
+	for _, item := range raw {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		selector, err := ParseLabelSelector(item)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
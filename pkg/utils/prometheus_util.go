@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// 라벨 값은 서버 ID, 상태 구분 등 개수가 제한된 값만 사용 (요청 ID, 쿼리는 라벨로 쓰지 않음)
+const prometheusNamespace = "ndns_router"
+
+// 프록시 폴백 사유
+const (
+	FallbackNoServer      = "no_server"      // 선택할 서버가 없어 서버리스 사용
+	FallbackUpstreamError = "upstream_error" // 서버 요청 실패 후 서버리스로 재시도
+)
+
+// SSE 메시지 전송 결과
+const (
+	SseMessageSent      = "sent"       // 채널에 전달
+	SseMessageDropped   = "dropped"    // 채널이 가득 차 유실
+	SseMessageNoClient  = "no_client"  // 연결된 클라이언트 없음
+	SseMessageQueued    = "queued"     // 구독자 연결 전이라 보관
+	SseMessageNotIssued = "not_issued" // 다른 인스턴스가 발급한 reqId라 보관하지 않음 (스팬에만 기록)
+)
+
+// 연결 전 SSE 메시지를 버린 사유
+const (
+	SseMailboxExpired  = "expired"  // 보관 기간 안에 구독자가 연결되지 않음
+	SseMailboxOverflow = "overflow" // reqId별 보관 개수 초과
+)
+
+// PrometheusMetrics는 라우터 자체 메트릭을 Prometheus 형식으로 노출합니다
+type PrometheusMetrics struct {
+	Registry *prometheus.Registry
+
+	upstreamRequests    *prometheus.CounterVec
+	upstreamLatency     *prometheus.HistogramVec
+	proxyRetries        prometheus.Counter
+	proxyFallbacks      *prometheus.CounterVec
+	serverlessDecisions *prometheus.CounterVec
+	sseMessages         *prometheus.CounterVec
+	sseMailboxEvictions *prometheus.CounterVec
+	sseReaped           *prometheus.CounterVec
+	eventsDropped       prometheus.Counter
+	accessLogDropped    prometheus.Counter
+	scrapes             *prometheus.CounterVec
+
+	serverlessForced atomic.Uint64
+	serverlessTotal  atomic.Uint64
+}
+
+// Prometheus 라우터 전역 메트릭
+var Prometheus = NewPrometheusMetrics()
+
+// NewPrometheusMetrics creates router metrics on a dedicated registry
+func NewPrometheusMetrics() *PrometheusMetrics {
+	p := &PrometheusMetrics{
+		Registry: prometheus.NewRegistry(),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "upstream_requests_total",
+			Help:      "업스트림 서버별 프록시 요청 수 (응답 상태 구분별)",
+		}, []string{"server_id", "status_class"}),
+		upstreamLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: prometheusNamespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "업스트림 서버별 프록시 응답 시간",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"server_id"}),
+		proxyRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "proxy_retries_total",
+			Help:      "서버 요청 실패 후 재시도 수",
+		}),
+		proxyFallbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "proxy_fallbacks_total",
+			Help:      "서버리스 폴백 수 (사유별)",
+		}, []string{"reason"}),
+		serverlessDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "serverless_decisions_total",
+			Help:      "서버리스 강제 사용 판정 수",
+		}, []string{"forced"}),
+		sseMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_messages_total",
+			Help:      "SSE 메시지 전송 결과별 수",
+		}, []string{"result"}),
+		sseMailboxEvictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_mailbox_evictions_total",
+			Help:      "구독자 연결 전에 도착했다가 버린 SSE 메시지 수 (사유별)",
+		}, []string{"reason"}),
+		sseReaped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_reaped_total",
+			Help:      "최대 연결 시간이나 유휴 시간을 넘겨 정리한 SSE 연결 수 (사유별)",
+		}, []string{"reason"}),
+		eventsDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "registry_events_dropped_total",
+			Help:      "구독자 버퍼가 가득 차 유실된 레지스트리 이벤트 수",
+		}),
+		accessLogDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "access_log_dropped_total",
+			Help:      "기록 큐가 가득 차 유실된 접근 로그 수",
+		}),
+		scrapes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Name:      "scrapes_total",
+			Help:      "서버 메트릭 수집 결과별 수",
+		}, []string{"result"}),
+	}
+
+	p.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.upstreamRequests,
+		p.upstreamLatency,
+		p.proxyRetries,
+		p.proxyFallbacks,
+		p.serverlessDecisions,
+		p.sseMessages,
+		p.sseMailboxEvictions,
+		p.sseReaped,
+		p.eventsDropped,
+		p.accessLogDropped,
+		p.scrapes,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "serverless_forced_ratio",
+			Help:      "서버리스 강제 사용 판정 비율 (누적)",
+		}, func() float64 {
+			total := p.serverlessTotal.Load()
+			if total == 0 {
+				return 0
+			}
+			return float64(p.serverlessForced.Load()) / float64(total)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_connections",
+			Help:      "현재 SSE 연결 수",
+		}, func() float64 {
+			return float64(Global.Count())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace,
+			Name:      "sse_pending_mailboxes",
+			Help:      "구독자 연결을 기다리며 메시지를 보관 중인 reqId 수",
+		}, func() float64 {
+			return float64(Global.PendingMailboxes())
+		}),
+	)
+
+	return p
+}
+
+// ObserveUpstream 업스트림 프록시 요청 결과 기록
+func (p *PrometheusMetrics) ObserveUpstream(serverId string, statusCode int, err error, latency time.Duration) {
+	p.upstreamRequests.WithLabelValues(serverId, statusClass(statusCode, err)).Inc()
+	p.upstreamLatency.WithLabelValues(serverId).Observe(latency.Seconds())
+}
+
+// IncRetry 서버 요청 실패 후 재시도 기록
+func (p *PrometheusMetrics) IncRetry() {
+	p.proxyRetries.Inc()
+}
+
+// IncFallback 서버리스 폴백 기록
+func (p *PrometheusMetrics) IncFallback(reason string) {
+	p.proxyFallbacks.WithLabelValues(reason).Inc()
+}
+
+// ObserveServerlessDecision 서버리스 강제 사용 판정 기록
+func (p *PrometheusMetrics) ObserveServerlessDecision(forced bool) {
+	p.serverlessTotal.Add(1)
+	if forced {
+		p.serverlessForced.Add(1)
+	}
+	p.serverlessDecisions.WithLabelValues(strconv.FormatBool(forced)).Inc()
+}
+
+// IncSseMessage SSE 메시지 전송 결과 기록
+func (p *PrometheusMetrics) IncSseMessage(result string) {
+	p.sseMessages.WithLabelValues(result).Inc()
+}
+
+// AddSseMailboxEvictions 버린 연결 전 SSE 메시지 수 기록
+func (p *PrometheusMetrics) AddSseMailboxEvictions(reason string, count int) {
+	p.sseMailboxEvictions.WithLabelValues(reason).Add(float64(count))
+}
+
+// IncSseReaped 정리한 SSE 연결 기록
+func (p *PrometheusMetrics) IncSseReaped(reason string) {
+	p.sseReaped.WithLabelValues(reason).Inc()
+}
+
+// IncEventDropped 유실된 레지스트리 이벤트 기록
+func (p *PrometheusMetrics) IncEventDropped() {
+	p.eventsDropped.Inc()
+}
+
+// IncAccessLogDropped 유실된 접근 로그 기록
+func (p *PrometheusMetrics) IncAccessLogDropped() {
+	p.accessLogDropped.Inc()
+}
+
+// ObserveScrape 서버 메트릭 수집 결과 기록
+func (p *PrometheusMetrics) ObserveScrape(success bool) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	p.scrapes.WithLabelValues(result).Inc()
+}
+
+// Handler Prometheus 텍스트 형식 응답 핸들러
+func (p *PrometheusMetrics) Handler() fasthttp.RequestHandler {
+	return fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(p.Registry, promhttp.HandlerOpts{}))
+}
+
+// statusClass 응답 상태 코드를 2xx, 4xx 등 구분으로 변환 (요청 실패는 error)
+func statusClass(statusCode int, err error) string {
+	if err != nil || statusCode < 100 || statusCode > 599 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
@@ -2,128 +2,648 @@ package utils
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/sh5080/ndns-router/pkg/interfaces"
 	"github.com/sh5080/ndns-router/pkg/types"
 	"github.com/sh5080/ndns-router/pkg/types/dtos"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// sseStream reqId 하나의 구독자와 재연결용 최근 이벤트
+type sseStream struct {
+	subscribers map[string]*types.ConnectionInfo // 구독자 ID → 연결 정보
+	events      []types.SseEvent                 // 재연결 시 다시 보낼 최근 이벤트 (오래된 순)
+	lastEventId uint64                           // 마지막으로 발급한 이벤트 ID
+	deliveredId uint64                           // 구독자에게 마지막으로 전달한 이벤트 ID (구독자가 없는 동안 쌓인 이벤트 구분용)
+}
+
+// sseMailbox 구독자가 연결되기 전에 도착한 reqId 메시지 (첫 구독자가 등록되면 순서대로 전달)
+type sseMailbox struct {
+	events      []types.SseEvent
+	lastEventId uint64
+	expiresAt   time.Time
+}
+
+// SSE 연결을 라우터가 종료한 사유 (마지막 close 이벤트로 전달)
+const (
+	SseCloseExpired      = "expired"       // 최대 연결 시간 초과
+	SseCloseIdle         = "idle"          // 유휴 시간 동안 클라이언트에 쓴 메시지나 하트비트 없음
+	SseCloseSlowConsumer = "slow_consumer" // 채널이 가득 차 메시지 유실
+)
+
+// SseOptions SSE 관리자의 연결 및 보관 한도
+type SseOptions struct {
+	MaxLifetime   time.Duration // 구독자 최대 연결 시간
+	IdleTimeout   time.Duration // 클라이언트에 메시지나 하트비트를 쓰지 못한 채 유지할 수 있는 시간
+	ReapInterval  time.Duration // 만료/유휴 연결 정리 주기
+	ReplaySize    int           // reqId마다 재연결용으로 보관할 최근 이벤트 수
+	ReplayTtl     time.Duration // 최근 이벤트 보관 기간
+	RetryInterval time.Duration // 클라이언트 재연결 대기 시간 (retry:)
+	MailboxSize   int           // reqId마다 연결 전 보관할 메시지 수 (넘치면 오래된 것부터 버림)
+	MailboxTtl    time.Duration // 연결 전 메시지 보관 기간 (첫 메시지 기준)
+	MaxMailboxes  int           // 동시에 보관할 수 있는 reqId 수
+	MaxIssued     int           // 연결 전 메시지를 보관하려고 기억하는 이 인스턴스가 발급한 reqId 수
+}
+
 type SseManager struct {
-	clients     sync.Map
-	validPeriod time.Duration // 연결 유효 기간
+	mutex            sync.RWMutex
+	clients          map[string]*sseStream  // reqId → 스트림
+	mailboxes        map[string]*sseMailbox // reqId → 연결 전 도착한 메시지
+	issued           map[string]time.Time   // 이 인스턴스가 발급한 reqId → 연결 전 메시지를 보관할 수 있는 기한
+	options          SseOptions
+	broker           interfaces.SseBroker // 인스턴스 간 메시지 발행/구독 (없으면 이 인스턴스에서만 전달)
+	nextId           atomic.Uint64        // 구독자 ID 발급용
+	lastEventId      atomic.Uint64        // 마지막으로 발행한 이벤트 ID
+	mailboxEvictions atomic.Uint64        // 만료되거나 넘쳐서 버린 연결 전 메시지 수
+	reapedExpired    atomic.Uint64        // 최대 연결 시간을 넘겨 정리한 연결 수
+	reapedIdle       atomic.Uint64        // 유휴 시간을 넘겨 정리한 연결 수
 }
 
-var Global = &SseManager{
-	validPeriod: 5 * time.Minute, // 5분으로 설정
+var Global = NewSseManager(SseOptions{
+	MaxLifetime:   5 * time.Minute,
+	IdleTimeout:   2 * time.Minute,
+	ReapInterval:  10 * time.Second,
+	ReplaySize:    50,
+	ReplayTtl:     5 * time.Minute,
+	RetryInterval: 3 * time.Second,
+	MailboxSize:   20,
+	MailboxTtl:    time.Minute,
+	MaxMailboxes:  10000,
+	MaxIssued:     100000,
+})
+
+// NewSseManager creates an SSE manager with the given retention limits
+func NewSseManager(options SseOptions) *SseManager {
+	return &SseManager{
+		clients:   make(map[string]*sseStream),
+		mailboxes: make(map[string]*sseMailbox),
+		issued:    make(map[string]time.Time),
+		options:   options,
+	}
+}
+
+// InitSse 설정에 따라 전역 SSE 관리자 생성 (라우터 설정 전에 호출)
+// 정리 주기가 0이거나 보관 한도가 음수면 실행 중에 패닉이 나고, 보관 한도가 0이면 조용히 보관하지 않으므로 시작 시 오류를 반환합니다
+func InitSse() error {
+	cfg := configs.GetConfig().Sse
+
+	positives := []struct {
+		name  string
+		valid bool
+		value any
+	}{
+		{"SSE_MAX_LIFETIME", cfg.MaxLifetime > 0, cfg.MaxLifetime},
+		{"SSE_IDLE_TIMEOUT", cfg.IdleTimeout > 0, cfg.IdleTimeout},
+		{"SSE_REAP_INTERVAL", cfg.ReapInterval > 0, cfg.ReapInterval},
+		{"SSE_REPLAY_TTL", cfg.ReplayTtl > 0, cfg.ReplayTtl},
+		{"SSE_RETRY_INTERVAL", cfg.RetryInterval > 0, cfg.RetryInterval},
+		{"SSE_MAILBOX_SIZE", cfg.MailboxSize > 0, cfg.MailboxSize},
+		{"SSE_MAILBOX_TTL", cfg.MailboxTtl > 0, cfg.MailboxTtl},
+		{"SSE_MAX_MAILBOXES", cfg.MaxMailboxes > 0, cfg.MaxMailboxes},
+		{"SSE_MAX_ISSUED", cfg.MaxIssued > 0, cfg.MaxIssued},
+	}
+	for _, option := range positives {
+		if !option.valid {
+			return fmt.Errorf("%s 값은 0보다 커야 합니다: %v", option.name, option.value)
+		}
+	}
+	// 하트비트만 오가는 정상 연결이 유휴 연결로 정리되지 않도록 함
+	if cfg.IdleTimeout <= configs.SseHeartbeatInterval {
+		return fmt.Errorf("SSE_IDLE_TIMEOUT 값은 하트비트 주기(%s)보다 커야 합니다: %s", configs.SseHeartbeatInterval, cfg.IdleTimeout)
+	}
+	// 0이면 재연결 시 다시 보낼 이벤트를 보관하지 않음
+	if cfg.ReplaySize < 0 {
+		return fmt.Errorf("SSE_REPLAY_SIZE 값은 0 이상이어야 합니다: %d", cfg.ReplaySize)
+	}
+
+	Global = NewSseManager(SseOptions{
+		MaxLifetime:   cfg.MaxLifetime,
+		IdleTimeout:   cfg.IdleTimeout,
+		ReapInterval:  cfg.ReapInterval,
+		ReplaySize:    cfg.ReplaySize,
+		ReplayTtl:     cfg.ReplayTtl,
+		RetryInterval: cfg.RetryInterval,
+		MailboxSize:   cfg.MailboxSize,
+		MailboxTtl:    cfg.MailboxTtl,
+		MaxMailboxes:  cfg.MaxMailboxes,
+		MaxIssued:     cfg.MaxIssued,
+	})
+	return nil
 }
 
-func (s *SseManager) Register(reqId string, ch chan string) {
-	Infof("[SSE] Register 시도 - reqId: %s", reqId)
+// RetryInterval 클라이언트에 보낼 재연결 대기 시간
+func (s *SseManager) RetryInterval() time.Duration {
+	return s.options.RetryInterval
+}
 
+// Register reqId에 새 구독자 등록 후 연결 정보와 먼저 보낼 이벤트 반환
+// 같은 reqId를 여러 탭에서 열거나 재연결하면 구독자마다 채널을 따로 두고 Send가 모두에게 전달합니다
+// 연결 전에 도착한 메시지가 있으면 모두, lastEventId가 0보다 크면 그 이후 이벤트를 반환하고,
+// lastEventId 없이 구독자가 모두 끊긴 스트림에 연결하면 구독자가 없는 동안 쌓인 이벤트를 반환합니다
+// 등록과 같은 잠금 안에서 꺼내므로 누락이나 중복 없이 채널로 이어집니다
+// 라우터가 연결을 끝내면 채널을 닫기 전에 연결 정보의 CloseReason을 채웁니다
+func (s *SseManager) Register(reqId string, ch chan types.SseEvent, lastEventId uint64) (*types.ConnectionInfo, []types.SseEvent) {
 	now := time.Now()
-	if old, ok := s.clients.Load(reqId); ok {
-		oldInfo := old.(*types.ConnectionInfo)
+	subscriberId := strconv.FormatUint(s.nextId.Add(1), 10)
 
-		// 만료되지 않은 채널이 있다면 재활용
-		if now.Before(oldInfo.ExpiresAt) {
-			Infof("[SSE] 유효한 기존 채널 발견, 기간 연장 - reqId: %s", reqId)
+	s.mutex.Lock()
+	s.sweepLocked(now)
+	stream, ok := s.clients[reqId]
+	if !ok {
+		stream = &sseStream{subscribers: make(map[string]*types.ConnectionInfo)}
+		s.clients[reqId] = stream
+		delete(s.issued, reqId)
 
-			// 기존 채널 유지하고 유효기간만 연장
-			oldInfo.ExpiresAt = now.Add(s.validPeriod)
-			oldInfo.LastActive = now
-			return
+		// 연결 전에 도착한 메시지는 재연결용 최근 이벤트로 옮기고 처음부터 전달
+		if mailbox, ok := s.mailboxes[reqId]; ok {
+			delete(s.mailboxes, reqId)
+			stream.events = mailbox.events
+			stream.lastEventId = mailbox.lastEventId
+			Infof("[SSE] 연결 전 도착한 메시지 %d개 전달 - reqId: %s", len(mailbox.events), reqId)
 		}
+	}
+	info := &types.ConnectionInfo{
+		SubscriberId: subscriberId,
+		Channel:      ch,
+		ConnectedAt:  now,
+		LastActive:   now,
+		Done:         make(chan struct{}),
+		ExpiresAt:    now.Add(s.options.MaxLifetime),
+	}
+	// 다른 구독자가 없으면 마지막 전달 이후 이벤트는 아무도 받지 못했으므로 Last-Event-ID가 없어도 다시 보냄
+	replayFrom, replaying := lastEventId, lastEventId > 0
+	if !replaying && len(stream.subscribers) == 0 {
+		replayFrom, replaying = stream.deliveredId, true
+	}
+	stream.subscribers[subscriberId] = info
 
-		// 만료된 채널은 정리
-		Infof("[SSE] 만료된 채널 발견, 정리 중 - reqId: %s", reqId)
-		close(oldInfo.Channel)
-		close(oldInfo.Done)
+	var replay []types.SseEvent
+	if replaying {
+		for _, event := range stream.events {
+			if event.Id > replayFrom {
+				replay = append(replay, event)
+			}
+		}
 	}
+	if len(replay) > 0 {
+		stream.deliveredId = max(stream.deliveredId, replay[len(replay)-1].Id)
+	}
+	count := len(stream.subscribers)
+	total := s.countLocked()
+	s.mutex.Unlock()
 
-	// 새 연결 등록
-	s.clients.Store(reqId, &types.ConnectionInfo{
-		Channel:     ch,
-		ConnectedAt: now,
-		LastActive:  now,
-		Done:        make(chan struct{}),
-		ExpiresAt:   now.Add(s.validPeriod),
-	})
+	Infof("[SSE] 구독자 등록 - reqId: %s, 구독자: %s (reqId 구독자 %d명, 다시 보낼 이벤트 %d개)", reqId, subscriberId, count, len(replay))
+	Statsd.ObserveSseConnect(total)
+	return info, replay
 }
 
 func (s *SseManager) GetActiveConnections() []types.Connection {
-	connections := make([]types.Connection, 0)
-	seen := make(map[string]bool)
 	now := time.Now()
 
-	s.clients.Range(func(key, value interface{}) bool {
-		reqId := key.(string)
-		if seen[reqId] {
-			Warnf("[SSE] 중복된 reqId 발견: %s", reqId)
-			return true
+	s.mutex.RLock()
+	connections := make([]types.Connection, 0, s.countLocked())
+	for reqId, stream := range s.clients {
+		for _, info := range stream.subscribers {
+			connections = append(connections, types.Connection{
+				ReqId:             reqId,
+				SubscriberId:      info.SubscriberId,
+				ConnectedAt:       info.ConnectedAt,
+				ConnectedDuration: now.Sub(info.ConnectedAt),
+				LastActive:        info.LastActive,
+				ExpiresIn:         info.ExpiresAt.Sub(now), // 만료까지 남은 시간
+			})
 		}
+	}
+	s.mutex.RUnlock()
 
-		info := value.(*types.ConnectionInfo)
-		// 만료까지 남은 시간 계산
-		expiresIn := info.ExpiresAt.Sub(now)
-
-		connections = append(connections, types.Connection{
-			ReqId:             reqId,
-			ConnectedAt:       info.ConnectedAt,
-			ConnectedDuration: now.Sub(info.ConnectedAt),
-			ExpiresIn:         expiresIn,
-		})
-		seen[reqId] = true
-		return true
+	// 연결 순서대로 정렬
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ConnectedAt.Before(connections[j].ConnectedAt)
 	})
-
 	return connections
 }
 
-func (s *SseManager) Send(reqId string, msg string) {
+// PendingMailboxes 구독자 연결을 기다리는 reqId 수
+func (s *SseManager) PendingMailboxes() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.mailboxes)
+}
+
+// MailboxEvictions 만료되거나 넘쳐서 버린 연결 전 메시지 수
+func (s *SseManager) MailboxEvictions() uint64 {
+	return s.mailboxEvictions.Load()
+}
+
+// ReapCounts 정리 사유별 정리한 연결 수
+func (s *SseManager) ReapCounts() map[string]uint64 {
+	return map[string]uint64{
+		SseCloseExpired: s.reapedExpired.Load(),
+		SseCloseIdle:    s.reapedIdle.Load(),
+	}
+}
+
+// Count 현재 등록된 SSE 구독자 수
+func (s *SseManager) Count() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.countLocked()
+}
+
+// countLocked 전체 구독자 수 (s.mutex를 잡은 상태에서 호출)
+func (s *SseManager) countLocked() int {
+	count := 0
+	for _, stream := range s.clients {
+		count += len(stream.subscribers)
+	}
+	return count
+}
+
+// Issue 이 인스턴스가 발급한 reqId 기록 (SSE 토큰 발급 시 호출)
+// 구독자가 연결되지 않은 reqId의 메시지는 발급한 인스턴스만 연결 전 메시지로 보관하므로,
+// 여러 인스턴스가 같은 메시지를 받아도 모두 보관하지 않습니다 (발급 후 SSE_MAILBOX_TTL 동안)
+func (s *SseManager) Issue(reqId string) {
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.issued) >= s.options.MaxIssued {
+		s.sweepIssuedLocked(now)
+		if len(s.issued) >= s.options.MaxIssued {
+			Warnf("[SSE] 발급한 reqId 기록 한도 초과, 연결 전 메시지를 보관하지 않음 - reqId: %s", reqId)
+			return
+		}
+	}
+	s.issued[reqId] = now.Add(s.options.MailboxTtl)
+}
+
+// UseBroker 인스턴스 간 메시지 발행/구독 통로 설정 후 구독 시작 (라우터 시작 전에 한 번 호출, ctx가 끝나면 구독 해지)
+// 설정하지 않으면 Send는 이 인스턴스의 구독자에게만 전달합니다
+func (s *SseManager) UseBroker(ctx context.Context, broker interfaces.SseBroker) error {
+	if err := broker.Subscribe(ctx, func(message *types.SseBrokerMessage) {
+		s.deliver(ExtractTraceMap(context.Background(), message.TraceContext), message)
+	}); err != nil {
+		return fmt.Errorf("SSE 메시지 구독 실패: %w", err)
+	}
+	s.broker = broker
+	return nil
+}
+
+// Send reqId 메시지 발행 (ctx의 스팬 아래에 발행 스팬 기록)
+// 발행한 메시지는 모든 인스턴스가 받아 각자 연결된 구독자에게 전달하며, 발행에 실패하면 이 인스턴스에서만 전달합니다
+func (s *SseManager) Send(ctx context.Context, reqId string, msg string) {
 	Infof("[SSE] Send 시도 - reqId: %s", reqId)
 
-	if value, ok := s.clients.Load(reqId); ok {
-		info := value.(*types.ConnectionInfo)
-		Infof("[SSE] 채널 찾음 - reqId: %s", reqId)
+	spanCtx, span := StartSpan(ctx, "sse.send", trace.WithAttributes(attribute.String("ndns.req_id", reqId)))
+	defer span.End()
+
+	now := time.Now()
+	message := &types.SseBrokerMessage{
+		ReqId:     reqId,
+		EventId:   s.nextEventId(now),
+		Message:   msg,
+		CreatedAt: now,
+	}
+	span.SetAttributes(attribute.Int64("ndns.sse.event_id", int64(message.EventId)))
+
+	if s.broker == nil {
+		s.deliver(spanCtx, message)
+		return
+	}
+
+	message.TraceContext = InjectTraceMap(spanCtx)
+	if err := s.broker.Publish(spanCtx, message); err != nil {
+		Warnf("[SSE] 메시지 발행 실패, 이 인스턴스에서만 전달 - reqId: %s (%v)", reqId, err)
+		span.RecordError(err)
+		s.deliver(spanCtx, message)
+	}
+}
+
+// nextEventId 발행할 이벤트 ID 발급 (마이크로초 시각 기반, 같은 인스턴스에서는 항상 증가)
+func (s *SseManager) nextEventId(now time.Time) uint64 {
+	for {
+		last := s.lastEventId.Load()
+		next := max(last+1, uint64(now.UnixMicro()))
+		if s.lastEventId.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// deliver 발행된 메시지를 이 인스턴스에 연결된 reqId의 모든 구독자 채널로 전달 (ctx의 스팬 아래에 전달 결과 스팬 기록)
+// 메시지는 이벤트 ID를 붙여 최근 이벤트로 보관하므로 구독자가 잠시 끊겼다 재연결해도 다시 받을 수 있습니다
+// 아직 구독자가 연결되지 않은 reqId면 이 인스턴스가 발급한 경우에만 연결 전 메시지로 보관했다가 첫 구독자 등록 시 전달합니다
+// 채널이 가득 찬 구독자는 연결을 정리합니다
+func (s *SseManager) deliver(ctx context.Context, message *types.SseBrokerMessage) {
+	reqId := message.ReqId
+	_, span := StartSpan(ctx, "sse.deliver", trace.WithAttributes(attribute.String("ndns.req_id", reqId)))
+	defer span.End()
 
-		select {
-		case info.Channel <- msg:
-			info.LastActive = time.Now() // 메시지 전송 성공 시 LastActive 업데이트
-			Infof("[SSE] 메시지 전송 성공 - reqId: %s, message: %s", reqId, msg)
-		default:
-			Warnf("[SSE] 메시지 전송 실패 (채널 막힘) - reqId: %s", reqId)
-			s.Deregister(reqId)
+	// 채널 전송은 막히지 않으므로 잠금을 잡은 채로 보냄 (Deregister가 채널을 닫는 것과 겹치지 않음)
+	now := time.Now()
+	proposed := types.SseEvent{
+		Id:        message.EventId,
+		Message:   message.Message,
+		CreatedAt: message.CreatedAt,
+	}
+	sent, total := 0, 0
+	queued, owned := false, true
+	var event types.SseEvent
+	s.mutex.Lock()
+	stream, ok := s.clients[reqId]
+	if !ok {
+		if owned = s.ownsLocked(reqId, now); owned {
+			event, queued = s.queueLocked(reqId, proposed, now)
 		}
 	} else {
-		Warnf("[SSE] 채널을 찾을 수 없음 - reqId: %s", reqId)
+		event = s.appendEventLocked(stream, proposed, now)
+		total = len(stream.subscribers)
+		for subscriberId, info := range stream.subscribers {
+			select {
+			case info.Channel <- event:
+				sent++
+				Prometheus.IncSseMessage(SseMessageSent)
+				Statsd.IncSseMessage(SseMessageSent)
+			default:
+				Warnf("[SSE] 메시지 전송 실패 (채널 막힘) - reqId: %s, 구독자: %s", reqId, subscriberId)
+				Prometheus.IncSseMessage(SseMessageDropped)
+				Statsd.IncSseMessage(SseMessageDropped)
+				s.deregisterLocked(reqId, subscriberId, SseCloseSlowConsumer)
+			}
+		}
+		if sent > 0 {
+			stream.deliveredId = event.Id
+		}
+	}
+	s.mutex.Unlock()
+
+	span.SetAttributes(attribute.Int("ndns.sse.subscribers", total), attribute.Int("ndns.sse.sent", sent))
+	if ok || queued {
+		span.SetAttributes(attribute.Int64("ndns.sse.event_id", int64(event.Id)))
+	}
+	switch {
+	case !owned:
+		Debugf("[SSE] 이 인스턴스가 발급하지 않았고 연결된 구독자도 없는 reqId, 보관하지 않음 - reqId: %s", reqId)
+		span.SetAttributes(attribute.String("ndns.sse.result", SseMessageNotIssued))
+	case queued:
+		Infof("[SSE] 구독자 연결 전, 메시지 보관 - reqId: %s, 이벤트: %d", reqId, event.Id)
+		Prometheus.IncSseMessage(SseMessageQueued)
+		Statsd.IncSseMessage(SseMessageQueued)
+		span.SetAttributes(attribute.String("ndns.sse.result", SseMessageQueued))
+	case total == 0:
+		if ok {
+			Infof("[SSE] 연결된 구독자 없음, 재연결 시 보낼 이벤트로 보관 - reqId: %s, 이벤트: %d", reqId, event.Id)
+		} else {
+			Warnf("[SSE] 채널을 찾을 수 없고 연결 전 메시지 보관 한도 초과 - reqId: %s", reqId)
+		}
+		Prometheus.IncSseMessage(SseMessageNoClient)
+		Statsd.IncSseMessage(SseMessageNoClient)
+		span.SetAttributes(attribute.String("ndns.sse.result", SseMessageNoClient))
+		span.SetStatus(codes.Error, "no client")
+	case sent == 0:
+		span.SetAttributes(attribute.String("ndns.sse.result", SseMessageDropped))
+		span.SetStatus(codes.Error, "channel full")
+	default:
+		span.SetAttributes(attribute.String("ndns.sse.result", SseMessageSent))
+		Infof("[SSE] 메시지 전송 성공 - reqId: %s, 이벤트: %d, 구독자: %d/%d, message: %s", reqId, event.Id, sent, total, message.Message)
+	}
+}
+
+// ownsLocked 구독자가 없는 reqId의 메시지를 이 인스턴스가 보관해야 하는지 확인 (s.mutex를 잡은 상태에서 호출)
+// 이미 보관 중이거나 이 인스턴스가 발급한 reqId만 보관합니다
+func (s *SseManager) ownsLocked(reqId string, now time.Time) bool {
+	if _, ok := s.mailboxes[reqId]; ok {
+		return true
+	}
+	expiresAt, ok := s.issued[reqId]
+	return ok && !now.After(expiresAt)
+}
+
+// queueLocked 구독자가 연결되기 전 reqId의 메시지 보관 (s.mutex를 잡은 상태에서 호출)
+// 보관할 수 있는 reqId 수를 넘으면 보관하지 않고 false 반환
+// event.Id는 발행 시 정한 ID이며, 이미 보관한 이벤트보다 작으면 다음 ID로 올려 보관합니다
+func (s *SseManager) queueLocked(reqId string, event types.SseEvent, now time.Time) (types.SseEvent, bool) {
+	mailbox, ok := s.mailboxes[reqId]
+	if ok && now.After(mailbox.expiresAt) {
+		s.evictMailboxLocked(reqId, mailbox, SseMailboxExpired)
+		ok = false
+	}
+	if !ok {
+		if len(s.mailboxes) >= s.options.MaxMailboxes {
+			s.sweepLocked(now)
+			if len(s.mailboxes) >= s.options.MaxMailboxes {
+				return types.SseEvent{}, false
+			}
+		}
+		mailbox = &sseMailbox{expiresAt: now.Add(s.options.MailboxTtl)}
+		s.mailboxes[reqId] = mailbox
+		delete(s.issued, reqId)
+	}
+
+	mailbox.lastEventId = max(mailbox.lastEventId+1, event.Id)
+	event.Id = mailbox.lastEventId
+	mailbox.events = append(mailbox.events, event)
+	if overflow := len(mailbox.events) - s.options.MailboxSize; overflow > 0 {
+		mailbox.events = mailbox.events[overflow:]
+		s.countMailboxEvictions(SseMailboxOverflow, overflow)
+		Warnf("[SSE] 연결 전 메시지 보관 한도 초과, 오래된 메시지 %d개 버림 - reqId: %s", overflow, reqId)
+	}
+	return event, true
+}
+
+// evictMailboxLocked 만료된 연결 전 메시지 삭제 (s.mutex를 잡은 상태에서 호출)
+func (s *SseManager) evictMailboxLocked(reqId string, mailbox *sseMailbox, reason string) {
+	delete(s.mailboxes, reqId)
+	s.countMailboxEvictions(reason, len(mailbox.events))
+	Warnf("[SSE] 구독자가 연결되지 않아 보관 중인 메시지 %d개 버림 - reqId: %s", len(mailbox.events), reqId)
+}
+
+// countMailboxEvictions 버린 연결 전 메시지 수 기록
+func (s *SseManager) countMailboxEvictions(reason string, count int) {
+	s.mailboxEvictions.Add(uint64(count))
+	Prometheus.AddSseMailboxEvictions(reason, count)
+	Statsd.AddSseMailboxEvictions(reason, count)
+}
+
+// appendEventLocked 이벤트를 최근 이벤트에 추가 (s.mutex를 잡은 상태에서 호출)
+// event.Id는 발행 시 정한 마이크로초 시각 기반 ID이며, reqId마다 항상 증가하도록 이전 ID보다 작으면 다음 ID로 올립니다
+func (s *SseManager) appendEventLocked(stream *sseStream, event types.SseEvent, now time.Time) types.SseEvent {
+	stream.lastEventId = max(stream.lastEventId+1, event.Id)
+	event.Id = stream.lastEventId
+
+	stream.events = append(stream.events, event)
+	if len(stream.events) > s.options.ReplaySize {
+		stream.events = stream.events[len(stream.events)-s.options.ReplaySize:]
+	}
+	s.trimEventsLocked(stream, now)
+	return event
+}
+
+// trimEventsLocked 보관 기간이 지난 최근 이벤트 정리 (s.mutex를 잡은 상태에서 호출)
+func (s *SseManager) trimEventsLocked(stream *sseStream, now time.Time) {
+	expired := 0
+	for expired < len(stream.events) && now.Sub(stream.events[expired].CreatedAt) > s.options.ReplayTtl {
+		expired++
+	}
+	if expired > 0 {
+		stream.events = append([]types.SseEvent(nil), stream.events[expired:]...)
+	}
+}
+
+// sweepLocked 구독자가 없고 보관 중인 이벤트도 만료된 스트림과 만료된 연결 전 메시지 정리 (s.mutex를 잡은 상태에서 호출)
+func (s *SseManager) sweepLocked(now time.Time) {
+	for reqId, mailbox := range s.mailboxes {
+		if now.After(mailbox.expiresAt) {
+			s.evictMailboxLocked(reqId, mailbox, SseMailboxExpired)
+		}
+	}
+
+	for reqId, stream := range s.clients {
+		if len(stream.subscribers) > 0 {
+			continue
+		}
+		s.trimEventsLocked(stream, now)
+		if len(stream.events) == 0 {
+			delete(s.clients, reqId)
+		}
+	}
+}
+
+// sweepIssuedLocked 보관 기한이 지난 발급 reqId 정리 (s.mutex를 잡은 상태에서 호출)
+func (s *SseManager) sweepIssuedLocked(now time.Time) {
+	for reqId, expiresAt := range s.issued {
+		if now.After(expiresAt) {
+			delete(s.issued, reqId)
+		}
+	}
+}
+
+// StartReaper 최대 연결 시간이나 유휴 시간을 넘긴 연결, 만료된 스트림과 연결 전 메시지를 주기적으로 정리 (ctx가 끝나면 중지)
+func (s *SseManager) StartReaper(ctx context.Context) {
+	Infof("[SSE] 연결 정리 시작 (최대 연결 시간: %s, 유휴 시간: %s, 주기: %s)", s.options.MaxLifetime, s.options.IdleTimeout, s.options.ReapInterval)
+	go func() {
+		ticker := time.NewTicker(s.options.ReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.reap(time.Now())
+			}
+		}
+	}()
+}
+
+// reap 만료/유휴 연결 종료 후 남은 스트림과 연결 전 메시지 정리
+func (s *SseManager) reap(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for reqId, stream := range s.clients {
+		for subscriberId, info := range stream.subscribers {
+			reason := ""
+			if now.After(info.ExpiresAt) {
+				reason = SseCloseExpired
+				s.reapedExpired.Add(1)
+			} else if now.Sub(info.LastActive) > s.options.IdleTimeout {
+				reason = SseCloseIdle
+				s.reapedIdle.Add(1)
+			}
+			if reason == "" {
+				continue
+			}
+
+			Infof("[SSE] 연결 정리 - reqId: %s, 구독자: %s (사유: %s)", reqId, subscriberId, reason)
+			Prometheus.IncSseReaped(reason)
+			Statsd.IncSseReaped(reason)
+			s.deregisterLocked(reqId, subscriberId, reason)
+		}
 	}
+	s.sweepLocked(now)
+	s.sweepIssuedLocked(now)
 }
 
-func (s *SseManager) Deregister(reqId string) {
-	Infof("[SSE] Deregister 시도 - reqId: %s", reqId)
+// Touch 구독자에게 메시지나 하트비트를 보낸 시각 기록 (유휴 연결 판단 기준)
+// 채널에 넣은 시점이 아니라 클라이언트에 실제로 쓴 시점을 기록해야 전달이 없는 동안에도 살아 있는 연결을 유지합니다
+func (s *SseManager) Touch(reqId string, subscriberId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	if value, ok := s.clients.Load(reqId); ok {
-		info := value.(*types.ConnectionInfo)
-		close(info.Done)
-		close(info.Channel)
-		s.clients.Delete(reqId)
-		Infof("[SSE] 채널 종료 및 삭제 완료 - reqId: %s", reqId)
+	if stream, ok := s.clients[reqId]; ok {
+		if info, ok := stream.subscribers[subscriberId]; ok {
+			info.LastActive = time.Now()
+		}
 	}
 }
 
+// Deregister 클라이언트가 끊긴 reqId의 구독자 연결 정리 (다른 구독자는 유지)
+func (s *SseManager) Deregister(reqId string, subscriberId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.deregisterLocked(reqId, subscriberId, "")
+}
+
+// deregisterLocked 구독자 채널을 닫고 삭제 (s.mutex를 잡은 상태에서 호출)
+// 라우터가 끊는 경우 reason을 남겨 스트림이 마지막 close 이벤트로 알리도록 합니다
+// 마지막 구독자가 나가도 재연결에 대비해 보관 중인 이벤트가 있으면 스트림은 남겨 둡니다
+func (s *SseManager) deregisterLocked(reqId string, subscriberId string, reason string) {
+	stream, ok := s.clients[reqId]
+	if !ok {
+		return
+	}
+	info, ok := stream.subscribers[subscriberId]
+	if !ok {
+		return
+	}
+	info.CloseReason = reason
+	close(info.Done)
+	close(info.Channel)
+	delete(stream.subscribers, subscriberId)
+	if len(stream.subscribers) == 0 && len(stream.events) == 0 {
+		delete(s.clients, reqId)
+	}
+
+	Statsd.ObserveSseDisconnect(s.countLocked())
+	Infof("[SSE] 채널 종료 및 삭제 완료 - reqId: %s, 구독자: %s", reqId, subscriberId)
+}
+
 func SendSseEvent(w *bufio.Writer, payload *dtos.SsePayload) error {
 	data, err := json.Marshal(payload.Data)
 	if err != nil {
 		return fmt.Errorf("JSON 직렬화 실패: %v", err)
 	}
 
-	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", payload.Type, string(data))
+	// 이벤트 ID가 있으면 함께 보내 브라우저가 재연결 시 Last-Event-ID로 돌려주도록 함
+	if payload.Id > 0 {
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", payload.Id, payload.Type, string(data))
+	} else {
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", payload.Type, string(data))
+	}
 	if err != nil {
 		return fmt.Errorf("SSE 메시지 전송 실패: %v", err)
 	}
 
 	return w.Flush()
 }
+
+// SendSseRetry 클라이언트 재연결 대기 시간(retry:) 전송
+func SendSseRetry(w *bufio.Writer, interval time.Duration) error {
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", interval.Milliseconds()); err != nil {
+		return fmt.Errorf("SSE 재연결 대기 시간 전송 실패: %v", err)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/types"
+)
+
+func newTestSseManager() *SseManager {
+	return NewSseManager(SseOptions{
+		MaxLifetime:   time.Hour,
+		IdleTimeout:   time.Minute,
+		ReapInterval:  time.Second,
+		ReplaySize:    10,
+		ReplayTtl:     time.Minute,
+		RetryInterval: time.Second,
+		MailboxSize:   10,
+		MailboxTtl:    time.Minute,
+		MaxMailboxes:  10,
+		MaxIssued:     10,
+	})
+}
+
+func TestSseReapClosesIdleSubscriber(t *testing.T) {
+	manager := newTestSseManager()
+	info, _ := manager.Register("req-1", make(chan types.SseEvent, 1), 0)
+
+	// 유휴 시간 동안 클라이언트에 아무것도 쓰지 못한 연결
+	manager.reap(time.Now().Add(2 * time.Minute))
+
+	select {
+	case <-info.Done:
+	default:
+		t.Fatal("유휴 연결이 정리되지 않음")
+	}
+	if info.CloseReason != SseCloseIdle {
+		t.Errorf("종료 사유: %q, 기대값: %q", info.CloseReason, SseCloseIdle)
+	}
+}
+
+func TestSseTouchKeepsSubscriberAlive(t *testing.T) {
+	manager := newTestSseManager()
+	info, _ := manager.Register("req-1", make(chan types.SseEvent, 1), 0)
+
+	// 전달할 메시지 없이 하트비트만 쓴 연결도 살아 있는 연결로 유지되어야 함
+	manager.mutex.Lock()
+	info.LastActive = time.Now().Add(-2 * time.Minute)
+	manager.mutex.Unlock()
+	manager.Touch("req-1", info.SubscriberId)
+
+	manager.reap(time.Now())
+
+	select {
+	case <-info.Done:
+		t.Fatalf("하트비트를 쓴 연결이 정리됨 (사유: %s)", info.CloseReason)
+	default:
+	}
+	if count := manager.Count(); count != 1 {
+		t.Errorf("구독자 수: %d, 기대값: 1", count)
+	}
+}
+
+func TestSseDeliverQueuesOnlyIssuedReqIds(t *testing.T) {
+	manager := newTestSseManager()
+	now := time.Now()
+
+	// 다른 인스턴스가 발급한 reqId는 구독자가 없으면 보관하지 않음
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "other", EventId: 1, Message: "a", CreatedAt: now})
+	if pending := manager.PendingMailboxes(); pending != 0 {
+		t.Fatalf("발급하지 않은 reqId의 메시지를 보관함: %d", pending)
+	}
+
+	manager.Issue("mine")
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "mine", EventId: 2, Message: "b", CreatedAt: now})
+	if pending := manager.PendingMailboxes(); pending != 1 {
+		t.Fatalf("발급한 reqId의 메시지를 보관하지 않음: %d", pending)
+	}
+
+	_, replay := manager.Register("mine", make(chan types.SseEvent, 1), 0)
+	if len(replay) != 1 || replay[0].Message != "b" {
+		t.Errorf("연결 전 메시지: %+v, 기대값: b 하나", replay)
+	}
+}
+
+func TestSseDeliverSendsToLocalSubscriberWithoutIssue(t *testing.T) {
+	manager := newTestSseManager()
+	ch := make(chan types.SseEvent, 1)
+	manager.Register("req-1", ch, 0)
+
+	// 다른 인스턴스가 발급했더라도 이 인스턴스에 연결된 구독자에게는 전달
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "req-1", EventId: 1, Message: "a", CreatedAt: time.Now()})
+
+	select {
+	case event := <-ch:
+		if event.Message != "a" {
+			t.Errorf("메시지: %q, 기대값: a", event.Message)
+		}
+	default:
+		t.Fatal("연결된 구독자에게 메시지가 전달되지 않음")
+	}
+}
+
+func TestSseIssuedReqIdExpires(t *testing.T) {
+	manager := newTestSseManager()
+	manager.Issue("mine")
+
+	// 발급 후 SSE_MAILBOX_TTL이 지나면 더 이상 보관하지 않음
+	manager.reap(time.Now().Add(2 * time.Minute))
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "mine", EventId: 1, Message: "a", CreatedAt: time.Now()})
+
+	if pending := manager.PendingMailboxes(); pending != 0 {
+		t.Errorf("기한이 지난 발급 reqId의 메시지를 보관함: %d", pending)
+	}
+}
+
+func TestSseRegisterReplaysEventsMissedWithoutSubscribers(t *testing.T) {
+	manager := newTestSseManager()
+	now := time.Now()
+
+	first, _ := manager.Register("req-1", make(chan types.SseEvent, 10), 0)
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "req-1", EventId: 1, Message: "a", CreatedAt: now})
+	manager.Deregister("req-1", first.SubscriberId)
+
+	// 구독자가 모두 끊긴 동안 도착한 이벤트
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "req-1", EventId: 2, Message: "b", CreatedAt: now})
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "req-1", EventId: 3, Message: "c", CreatedAt: now})
+
+	// Last-Event-ID 없이 연결해도 아무도 받지 못한 이벤트는 다시 보냄
+	_, replay := manager.Register("req-1", make(chan types.SseEvent, 10), 0)
+	if len(replay) != 2 || replay[0].Message != "b" || replay[1].Message != "c" {
+		t.Fatalf("다시 보낸 이벤트: %+v, 기대값: b, c", replay)
+	}
+
+	// 다른 구독자가 연결되어 있으면 Last-Event-ID 없는 새 구독자는 이후 이벤트만 받음
+	_, replay = manager.Register("req-1", make(chan types.SseEvent, 10), 0)
+	if len(replay) != 0 {
+		t.Errorf("연결된 구독자가 있는 스트림의 새 구독자에게 다시 보낸 이벤트: %+v", replay)
+	}
+}
+
+func TestSseRegisterDoesNotReplayDeliveredEvents(t *testing.T) {
+	manager := newTestSseManager()
+
+	first, _ := manager.Register("req-1", make(chan types.SseEvent, 10), 0)
+	manager.deliver(context.Background(), &types.SseBrokerMessage{ReqId: "req-1", EventId: 1, Message: "a", CreatedAt: time.Now()})
+	manager.Deregister("req-1", first.SubscriberId)
+
+	// 끊기기 전에 전달한 이벤트는 Last-Event-ID 없는 새 구독자에게 다시 보내지 않음
+	_, replay := manager.Register("req-1", make(chan types.SseEvent, 10), 0)
+	if len(replay) != 0 {
+		t.Errorf("이미 전달한 이벤트를 다시 보냄: %+v", replay)
+	}
+}
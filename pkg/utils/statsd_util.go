@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+)
+
+// StatsD 태그 형식
+const (
+	StatsdFormatStatsd    = "statsd"    // 태그 값을 메트릭 이름에 붙임 (예: upstream.requests.api-1.2xx)
+	StatsdFormatDogstatsd = "dogstatsd" // |#key:value 태그 사용
+)
+
+// StatsdClient는 StatsD/DogStatsD 형식 메트릭을 UDP로 보냅니다
+// 메트릭은 큐에 넣기만 하고 백그라운드에서 패킷 크기만큼 묶어 보내므로 요청 처리를 막지 않습니다
+// (큐가 가득 차면 버림)
+type StatsdClient struct {
+	enabled       bool
+	dogstatsd     bool
+	prefix        string
+	globalTags    []string
+	conn          net.Conn
+	queue         chan string
+	maxPacketSize int
+	flushInterval time.Duration
+	dropped       atomic.Uint64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Statsd 라우터 전역 StatsD 클라이언트 (InitStatsd 전에는 아무것도 보내지 않음)
+var Statsd = &StatsdClient{}
+
+// InitStatsd 설정에 따라 전역 StatsD 클라이언트 생성 (STATSD_ADDR가 없으면 비활성화)
+func InitStatsd() error {
+	cfg := configs.GetConfig().Statsd
+	if cfg.Address == "" {
+		return nil
+	}
+
+	client, err := NewStatsdClient(cfg.Address, cfg.Format, cfg.Prefix, cfg.Tags, cfg.FlushInterval, cfg.MaxPacketSize, cfg.QueueSize)
+	if err != nil {
+		return err
+	}
+
+	Statsd = client
+	Infof("StatsD 전송 설정 완료 (%s, 형식: %s)", cfg.Address, cfg.Format)
+	return nil
+}
+
+// NewStatsdClient creates a StatsD client sending batched UDP packets to address
+func NewStatsdClient(address string, format string, prefix string, tags []string, flushInterval time.Duration, maxPacketSize int, queueSize int) (*StatsdClient, error) {
+	format = strings.ToLower(format)
+	if format != StatsdFormatStatsd && format != StatsdFormatDogstatsd {
+		return nil, fmt.Errorf("알 수 없는 STATSD_FORMAT: %s", format)
+	}
+	if flushInterval <= 0 || maxPacketSize <= 0 || queueSize <= 0 {
+		return nil, fmt.Errorf("StatsD 전송 주기, 패킷 크기, 큐 크기는 0보다 커야 합니다")
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("StatsD 주소 연결 실패: %w", err)
+	}
+
+	client := &StatsdClient{
+		enabled:       true,
+		dogstatsd:     format == StatsdFormatDogstatsd,
+		prefix:        prefix,
+		globalTags:    tags,
+		conn:          conn,
+		queue:         make(chan string, queueSize),
+		maxPacketSize: maxPacketSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go client.run()
+	return client, nil
+}
+
+// Incr 카운터 1 증가
+func (s *StatsdClient) Incr(name string, tags ...string) {
+	s.Count(name, 1, tags...)
+}
+
+// Count 카운터 증가
+func (s *StatsdClient) Count(name string, value int64, tags ...string) {
+	if !s.enabled {
+		return
+	}
+	s.enqueue(name, strconv.FormatInt(value, 10), "c", tags)
+}
+
+// Timing 시간 기록 (ms)
+func (s *StatsdClient) Timing(name string, duration time.Duration, tags ...string) {
+	if !s.enabled {
+		return
+	}
+	s.enqueue(name, strconv.FormatFloat(float64(duration.Microseconds())/1000, 'f', -1, 64), "ms", tags)
+}
+
+// Gauge 현재 값 기록
+func (s *StatsdClient) Gauge(name string, value float64, tags ...string) {
+	if !s.enabled {
+		return
+	}
+	s.enqueue(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// ObserveUpstream 업스트림 요청 결과와 지연 시간 기록
+func (s *StatsdClient) ObserveUpstream(serverId string, statusCode int, err error, latency time.Duration) {
+	s.Incr("upstream.requests", "server_id:"+serverId, "status_class:"+statusClass(statusCode, err))
+	s.Timing("upstream.latency", latency, "server_id:"+serverId)
+}
+
+// ObserveSelection 프록시 서버 선택 결과 기록 (선택된 서버가 없으면 server_id:none)
+func (s *StatsdClient) ObserveSelection(serverId string, spill string) {
+	if serverId == "" {
+		serverId = "none"
+	}
+	if spill == "" {
+		spill = "none"
+	}
+	s.Incr("proxy.selection", "server_id:"+serverId, "spill:"+spill)
+}
+
+// ObserveServerlessDecision 서버리스 강제 사용 판정 기록
+func (s *StatsdClient) ObserveServerlessDecision(forced bool) {
+	s.Incr("proxy.serverless_decision", "forced:"+strconv.FormatBool(forced))
+}
+
+// IncRetry 다른 서버로 재시도한 요청 수 증가
+func (s *StatsdClient) IncRetry() {
+	s.Incr("proxy.retries")
+}
+
+// IncFallback 서버리스 폴백 수 증가
+func (s *StatsdClient) IncFallback(reason string) {
+	s.Incr("proxy.fallbacks", "reason:"+reason)
+}
+
+// ObserveSseConnect SSE 연결 기록
+func (s *StatsdClient) ObserveSseConnect(connections int) {
+	s.Incr("sse.connects")
+	s.Gauge("sse.connections", float64(connections))
+}
+
+// ObserveSseDisconnect SSE 연결 종료 기록
+func (s *StatsdClient) ObserveSseDisconnect(connections int) {
+	s.Incr("sse.disconnects")
+	s.Gauge("sse.connections", float64(connections))
+}
+
+// IncSseMessage SSE 메시지 전달 결과 수 증가 (dropped, no_client는 버려진 메시지)
+func (s *StatsdClient) IncSseMessage(result string) {
+	s.Incr("sse.messages", "result:"+result)
+}
+
+// AddSseMailboxEvictions 구독자 연결 전에 도착했다가 버린 SSE 메시지 수 기록
+func (s *StatsdClient) AddSseMailboxEvictions(reason string, count int) {
+	s.Count("sse.mailbox_evictions", int64(count), "reason:"+reason)
+}
+
+// IncSseReaped 최대 연결 시간이나 유휴 시간을 넘겨 정리한 SSE 연결 수 증가
+func (s *StatsdClient) IncSseReaped(reason string) {
+	s.Incr("sse.reaped", "reason:"+reason)
+}
+
+// Dropped 큐가 가득 차 버린 메트릭 수
+func (s *StatsdClient) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close 남은 메트릭을 보내고 연결 종료
+func (s *StatsdClient) Close() error {
+	if !s.enabled {
+		return nil
+	}
+
+	s.closeOnce.Do(func() {
+		close(s.queue)
+		<-s.done
+		if dropped := s.Dropped(); dropped > 0 {
+			Warnf("StatsD 큐가 가득 차 버린 메트릭: %d개", dropped)
+		}
+	})
+	return s.conn.Close()
+}
+
+// enqueue 메트릭 한 줄을 만들어 큐에 넣음 (가득 차면 버림)
+// tags는 "key:value" 형식입니다
+func (s *StatsdClient) enqueue(name string, value string, metricType string, tags []string) {
+	var line strings.Builder
+	line.WriteString(s.prefix)
+	line.WriteString(name)
+
+	if !s.dogstatsd {
+		// 태그를 지원하지 않으므로 태그 값을 이름 뒤에 붙임
+		for _, tag := range tags {
+			line.WriteByte('.')
+			line.WriteString(sanitizeStatsdName(tagValue(tag)))
+		}
+	}
+
+	line.WriteByte(':')
+	line.WriteString(value)
+	line.WriteByte('|')
+	line.WriteString(metricType)
+
+	if s.dogstatsd && len(tags)+len(s.globalTags) > 0 {
+		line.WriteString("|#")
+		line.WriteString(strings.Join(append(append(make([]string, 0, len(tags)+len(s.globalTags)), s.globalTags...), tags...), ","))
+	}
+
+	select {
+	case s.queue <- line.String():
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run 큐의 메트릭을 패킷 크기만큼 묶어 전송 (전송 주기마다 남은 메트릭도 전송)
+func (s *StatsdClient) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	buffer := make([]byte, 0, s.maxPacketSize)
+	flush := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		// UDP 전송 실패는 무시 (수집기가 없어도 라우터 동작에 영향 없음)
+		s.conn.Write(buffer)
+		buffer = buffer[:0]
+	}
+
+	for {
+		select {
+		case line, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if len(buffer) > 0 && len(buffer)+1+len(line) > s.maxPacketSize {
+				flush()
+			}
+			if len(buffer) > 0 {
+				buffer = append(buffer, '\n')
+			}
+			buffer = append(buffer, line...)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// tagValue "key:value" 태그의 값
+func tagValue(tag string) string {
+	if index := strings.IndexByte(tag, ':'); index >= 0 {
+		return tag[index+1:]
+	}
+	return tag
+}
+
+// sanitizeStatsdName 메트릭 이름에 쓸 수 없는 문자(:, |, @, 공백, .)를 _로 변경
+func sanitizeStatsdName(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', ' ', '.', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,226 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenStatsd 로컬 UDP 수집기 (받은 패킷을 그대로 반환)
+func listenStatsd(t *testing.T) (*net.UDPConn, string) {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("UDP 수신 대기 실패: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, conn.LocalAddr().String()
+}
+
+// readPackets 수집기가 더 이상 받지 않을 때까지 패킷을 읽음
+func readPackets(t *testing.T, conn *net.UDPConn) []string {
+	t.Helper()
+
+	var packets []string
+	buffer := make([]byte, 65536)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, err := conn.Read(buffer)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return packets
+			}
+			t.Fatalf("UDP 패킷 읽기 실패: %v", err)
+		}
+		packets = append(packets, string(buffer[:n]))
+	}
+}
+
+// readLines 받은 패킷을 메트릭 한 줄씩 분리
+func readLines(t *testing.T, conn *net.UDPConn) []string {
+	t.Helper()
+
+	var lines []string
+	for _, packet := range readPackets(t, conn) {
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	return lines
+}
+
+func assertLines(t *testing.T, got []string, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("메트릭 수: %d, 기대값: %d\n받음: %q", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("메트릭 %d: %q, 기대값: %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStatsdClientDogstatsdFormat(t *testing.T) {
+	conn, address := listenStatsd(t)
+
+	client, err := NewStatsdClient(address, StatsdFormatDogstatsd, "ndns.", []string{"env:test"}, time.Hour, 1432, 100)
+	if err != nil {
+		t.Fatalf("StatsD 클라이언트 생성 실패: %v", err)
+	}
+
+	client.ObserveUpstream("api-1", 503, nil, 12500*time.Microsecond)
+	client.ObserveSelection("", "")
+	client.Gauge("sse.connections", 3)
+	client.IncFallback("upstream_error")
+
+	// 전송 주기가 길어도 Close가 남은 메트릭을 보내야 함
+	if err := client.Close(); err != nil {
+		t.Fatalf("StatsD 클라이언트 종료 실패: %v", err)
+	}
+
+	assertLines(t, readLines(t, conn), []string{
+		"ndns.upstream.requests:1|c|#env:test,server_id:api-1,status_class:5xx",
+		"ndns.upstream.latency:12.5|ms|#env:test,server_id:api-1",
+		"ndns.proxy.selection:1|c|#env:test,server_id:none,spill:none",
+		"ndns.sse.connections:3|g|#env:test",
+		"ndns.proxy.fallbacks:1|c|#env:test,reason:upstream_error",
+	})
+}
+
+func TestStatsdClientStatsdFormatAppendsTagValues(t *testing.T) {
+	conn, address := listenStatsd(t)
+
+	client, err := NewStatsdClient(address, StatsdFormatStatsd, "ndns.", []string{"env:test"}, time.Hour, 1432, 100)
+	if err != nil {
+		t.Fatalf("StatsD 클라이언트 생성 실패: %v", err)
+	}
+
+	// 태그 값의 '.', ':', 공백은 메트릭 이름을 깨뜨리므로 _로 바뀌어야 함
+	client.ObserveUpstream("api.1:8080", 0, errors.New("connection refused"), time.Millisecond)
+	client.Count("sse.mailbox_evictions", 2, "reason:max mailboxes")
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("StatsD 클라이언트 종료 실패: %v", err)
+	}
+
+	assertLines(t, readLines(t, conn), []string{
+		"ndns.upstream.requests.api_1_8080.error:1|c",
+		"ndns.upstream.latency.api_1_8080:1|ms",
+		"ndns.sse.mailbox_evictions.max_mailboxes:2|c",
+	})
+}
+
+func TestStatsdClientBatchesWithinPacketSize(t *testing.T) {
+	conn, address := listenStatsd(t)
+
+	const maxPacketSize = 64
+	client, err := NewStatsdClient(address, StatsdFormatStatsd, "", nil, time.Hour, maxPacketSize, 100)
+	if err != nil {
+		t.Fatalf("StatsD 클라이언트 생성 실패: %v", err)
+	}
+
+	want := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		client.IncRetry()
+		want = append(want, "proxy.retries:1|c")
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("StatsD 클라이언트 종료 실패: %v", err)
+	}
+
+	packets := readPackets(t, conn)
+	if len(packets) < 2 {
+		t.Fatalf("패킷 크기를 넘는 메트릭은 여러 패킷으로 나뉘어야 함: %d개", len(packets))
+	}
+	var lines []string
+	for _, packet := range packets {
+		if len(packet) > maxPacketSize {
+			t.Errorf("패킷 크기 %d가 최대 %d를 넘음: %q", len(packet), maxPacketSize, packet)
+		}
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	assertLines(t, lines, want)
+}
+
+func TestStatsdClientFlushesOnInterval(t *testing.T) {
+	conn, address := listenStatsd(t)
+
+	client, err := NewStatsdClient(address, StatsdFormatStatsd, "", nil, 20*time.Millisecond, 1432, 100)
+	if err != nil {
+		t.Fatalf("StatsD 클라이언트 생성 실패: %v", err)
+	}
+	defer client.Close()
+
+	client.IncRetry()
+
+	// Close 없이 전송 주기만으로 전송되어야 함
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buffer := make([]byte, 1432)
+	n, err := conn.Read(buffer)
+	if err != nil {
+		t.Fatalf("전송 주기 안에 메트릭을 받지 못함: %v", err)
+	}
+	if got := string(buffer[:n]); got != "proxy.retries:1|c" {
+		t.Errorf("메트릭: %q, 기대값: %q", got, "proxy.retries:1|c")
+	}
+}
+
+func TestStatsdClientDropsWhenQueueFull(t *testing.T) {
+	_, address := listenStatsd(t)
+
+	// 전송 고루틴 없이 작은 큐를 써서 가득 찬 상태를 만듦
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		t.Fatalf("UDP 연결 실패: %v", err)
+	}
+	defer conn.Close()
+	client := &StatsdClient{enabled: true, conn: conn, queue: make(chan string, 2)}
+
+	for i := 0; i < 5; i++ {
+		client.IncRetry()
+	}
+
+	if dropped := client.Dropped(); dropped != 3 {
+		t.Errorf("버린 메트릭 수: %d, 기대값: 3", dropped)
+	}
+}
+
+func TestNewStatsdClientRejectsInvalidOptions(t *testing.T) {
+	_, address := listenStatsd(t)
+
+	tests := []struct {
+		name          string
+		format        string
+		flushInterval time.Duration
+		maxPacketSize int
+		queueSize     int
+	}{
+		{"unknown format", "graphite", time.Second, 1432, 100},
+		{"zero flush interval", StatsdFormatStatsd, 0, 1432, 100},
+		{"zero packet size", StatsdFormatStatsd, time.Second, 0, 100},
+		{"zero queue size", StatsdFormatStatsd, time.Second, 1432, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewStatsdClient(address, tt.format, "", nil, tt.flushInterval, tt.maxPacketSize, tt.queueSize); err == nil {
+				t.Error("잘못된 설정은 오류를 반환해야 함")
+			}
+		})
+	}
+}
+
+func TestStatsdClientDisabledIsNoop(t *testing.T) {
+	client := &StatsdClient{}
+
+	client.IncRetry()
+	client.Gauge("sse.connections", 1)
+
+	if err := client.Close(); err != nil {
+		t.Errorf("비활성화된 클라이언트 종료 오류: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sh5080/ndns-router/pkg/configs"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 트레이스 내보내기 종류
+const (
+	TraceExporterNone   = "none"   // 전파만 하고 내보내지 않음
+	TraceExporterStdout = "stdout" // 표준 출력 (개발용)
+	TraceExporterOtlp   = "otlp"   // OTLP/HTTP 수집기
+)
+
+// Tracer 라우터 전역 트레이서 (InitTracing 전에는 내보내지 않는 트레이서로 동작)
+var Tracer = otel.Tracer("github.com/sh5080/ndns-router")
+
+// InitTracing 설정에 따라 트레이스 내보내기와 W3C traceparent 전파 설정
+// 반환된 함수는 종료 시 남은 스팬을 내보냅니다
+func InitTracing(ctx context.Context) (func(context.Context) error, error) {
+	cfg := configs.GetConfig().Tracing
+
+	// 내보내지 않더라도 traceparent는 백엔드로 그대로 전달
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	options := make([]sdktrace.TracerProviderOption, 0)
+	for _, name := range strings.Split(cfg.Exporter, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "", TraceExporterNone:
+		case TraceExporterStdout:
+			exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+			if err != nil {
+				return nil, fmt.Errorf("stdout 트레이스 내보내기 생성 실패: %w", err)
+			}
+			options = append(options, sdktrace.WithBatcher(exporter))
+		case TraceExporterOtlp:
+			otlpOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OtlpEndpoint)}
+			if cfg.OtlpInsecure {
+				otlpOptions = append(otlpOptions, otlptracehttp.WithInsecure())
+			}
+			exporter, err := otlptracehttp.New(ctx, otlpOptions...)
+			if err != nil {
+				return nil, fmt.Errorf("OTLP 트레이스 내보내기 생성 실패: %w", err)
+			}
+			options = append(options, sdktrace.WithBatcher(exporter))
+		default:
+			return nil, fmt.Errorf("알 수 없는 TRACING_EXPORTER: %s", name)
+		}
+	}
+
+	if len(options) == 0 {
+		Info("트레이스 내보내기 없음 (traceparent 전파만 사용)")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+
+	options = append(options,
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	Infof("트레이스 내보내기 설정 완료 (%s, 샘플링 비율: %.2f)", cfg.Exporter, cfg.SampleRatio)
+	return provider.Shutdown, nil
+}
+
+// ExtractTraceContext 요청 헤더의 traceparent를 부모로 하는 컨텍스트 생성
+func ExtractTraceContext(ctx context.Context, header *fasthttp.RequestHeader) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, requestHeaderCarrier{header: header})
+}
+
+// InjectTraceContext 현재 스팬을 traceparent로 요청 헤더에 기록 (기존 값은 교체)
+func InjectTraceContext(ctx context.Context, header *fasthttp.RequestHeader) {
+	otel.GetTextMapPropagator().Inject(ctx, requestHeaderCarrier{header: header})
+}
+
+// InjectTraceMap 현재 스팬을 traceparent로 담은 맵 반환 (인스턴스 사이에 발행하는 메시지에 함께 전달)
+func InjectTraceMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// ExtractTraceMap 메시지에 담긴 traceparent를 부모로 하는 컨텍스트 생성
+func ExtractTraceMap(ctx context.Context, traceContext map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+// StartSpan 전역 트레이서로 스팬 시작
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer.Start(ctx, name, opts...)
+}
+
+// requestHeaderCarrier는 fasthttp 요청 헤더를 전파 대상 형식으로 감쌉니다
+type requestHeaderCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (c requestHeaderCarrier) Get(key string) string {
+	return string(c.header.Peek(key))
+}
+
+func (c requestHeaderCarrier) Set(key string, value string) {
+	c.header.Set(key, value)
+}
+
+func (c requestHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ServerIdFromUrl 서버 URL에서 서버 ID 생성
+// 도메인은 첫 번째 라벨을 사용하고 (https://api1.ndns.site -> ndns-api1),
+// IP 주소는 host-port 형식을 사용합니다 (http://10.0.0.1:3000 -> ndns-10.0.0.1-3000)
+func ServerIdFromUrl(rawUrl string) string {
+	if !strings.Contains(rawUrl, "://") {
+		rawUrl = "https://" + rawUrl
+	}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+
+	host := parsed.Hostname()
+	if net.ParseIP(host) != nil {
+		if port := parsed.Port(); port != "" {
+			return "ndns-" + host + "-" + port
+		}
+		return "ndns-" + host
+	}
+
+	return "ndns-" + strings.Split(host, ".")[0]
+}